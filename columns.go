@@ -0,0 +1,98 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+const (
+	notColumnMsg       = "the column '%s' must be a slice or an array but a %s was given"
+	columnLengthMsg    = "the column '%s' has %d values but the column '%s' has %d"
+	notColumnsInputMsg = "the input argument must be a map with string keys but a %s was given"
+)
+
+// columnsToRows converts a map of columns into a slice of rows, checking that the columns are of equal length.
+func columnsToRows(input reflect.Value) ([]map[string]interface{}, error) {
+	names := make([]string, 0, input.Len())
+	columns := make(map[string]reflect.Value, input.Len())
+	mapRange := input.MapRange()
+	for mapRange.Next() {
+		key := mapRange.Key()
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		if key.Kind() != reflect.String {
+			return nil, fmt.Errorf(notColumnsInputMsg, "map with "+describeType(key)+" keys")
+		}
+		column := mapRange.Value()
+		for column.Kind() == reflect.Ptr || column.Kind() == reflect.Interface {
+			column = column.Elem()
+		}
+		if column.Kind() != reflect.Slice && column.Kind() != reflect.Array {
+			return nil, fmt.Errorf(notColumnMsg, key.String(), column.Kind().String())
+		}
+		names = append(names, key.String())
+		columns[key.String()] = column
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	sort.Strings(names)
+
+	length := columns[names[0]].Len()
+	for _, name := range names[1:] {
+		if columns[name].Len() != length {
+			return nil, fmt.Errorf(columnLengthMsg, name, columns[name].Len(), names[0], length)
+		}
+	}
+	rows := make([]map[string]interface{}, length)
+	for i := range rows {
+		rows[i] = make(map[string]interface{}, len(names))
+		for _, name := range names {
+			rows[i][name] = columns[name].Index(i).Interface()
+		}
+	}
+	return rows, nil
+}
+
+// rowsToColumns converts a slice or an array of structs, or of pointers to structs, into a map of columns keyed as
+// StructToMap keys fields. A nil row has a nil value in every column.
+func (d *Decoder) rowsToColumns(input reflect.Value, structType reflect.Type) (map[string]interface{}, error) {
+	columns := make(map[string][]interface{}, structType.NumField())
+	info := d.structInfo(structType)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		switch parts, ok := info.paths[i]; {
+		case !d.inGroups(field) || omitted(field, d.Tags) || i == info.remainIndex:
+		case ok:
+			columns[parts[0]] = make([]interface{}, input.Len())
+		case field.PkgPath == "" && field.Tag.Get(prefixTag) == "":
+			columns[fieldKey(field, d.Tags)] = make([]interface{}, input.Len())
+		}
+	}
+	for i := 0; i < input.Len(); i++ {
+		row := input.Index(i)
+		for row.Kind() == reflect.Ptr && !row.IsNil() {
+			row = row.Elem()
+		}
+		if row.Kind() == reflect.Ptr {
+			continue
+		}
+		output, err := d.structToMap(row)
+		if err != nil {
+			return nil, fmt.Errorf("%s"+rowSuffix, err, i+1)
+		}
+		for key, value := range output {
+			if _, ok := columns[key]; !ok {
+				columns[key] = make([]interface{}, input.Len())
+			}
+			columns[key][i] = value
+		}
+	}
+	output := make(map[string]interface{}, len(columns))
+	for key, column := range columns {
+		output[key] = column
+	}
+	return output, nil
+}
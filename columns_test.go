@@ -0,0 +1,100 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+func TestColumnsToStructs(t *testing.T) {
+	columns := map[string]interface{}{
+		"id":   []interface{}{float64(213), float64(56)},
+		"name": []string{"Zhaoliu", "Zhangsan"},
+		"sex":  [2]string{"male", "male"},
+	}
+
+	var users []UserWithTags
+
+	err := mapstostructs.ColumnsToStructs(columns, &users, "alias")
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []UserWithTags{
+			{ID: 213, Name: "Zhaoliu", Gender: "male"},
+			{ID: 56, Name: "Zhangsan", Gender: "male"},
+		}, users, "rows should be built from the columns")
+	}
+
+	pointers, err := mapstostructs.ColumnsToStructsOf[*User](map[string][]interface{}{"id": {1}})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []*User{{ID: 1}}, pointers, "rows should be built from the columns")
+	}
+}
+
+func TestColumnsToStructsErrors(t *testing.T) {
+	var users []User
+
+	err := mapstostructs.ColumnsToStructs(map[string]interface{}{
+		"id":   []int{1, 2},
+		"name": []string{"a"},
+	}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with mismatched columns") {
+		assert.Equal(t, "the column 'name' has 1 values but the column 'id' has 2", err.Error())
+	}
+
+	err = mapstostructs.ColumnsToStructs(map[string]interface{}{"id": 1}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with a column which is not a slice") {
+		assert.Equal(t, "the column 'id' must be a slice or an array but a int was given", err.Error())
+	}
+
+	err = mapstostructs.ColumnsToStructs(map[string]interface{}{"id": []string{"1", "x"}}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the ID field for a struct of type User must be or be convertible to int type, but received '1' in row 1", err.Error())
+	}
+
+	err = mapstostructs.ColumnsToStructs([]int{1}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid input") {
+		assert.Equal(t, "the input argument must be a map with string keys but a slice was given", err.Error())
+	}
+
+	err = mapstostructs.ColumnsToStructs(map[int]interface{}{1: []int{1}}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid input") {
+		assert.Equal(t, "the input argument must be a map with string keys but a map with int keys was given", err.Error())
+	}
+}
+
+func TestStructsToColumns(t *testing.T) {
+	users := []*UserWithTags{{ID: 213, Name: "Zhaoliu", Gender: "male"}, nil}
+
+	columns, err := mapstostructs.StructsToColumns(users, "alias")
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{
+			"id":   []interface{}{213, nil},
+			"name": []interface{}{"Zhaoliu", nil},
+			"sex":  []interface{}{"male", nil},
+			"Age":  []interface{}{0, nil},
+		}, columns, "columns should be built from the rows")
+	}
+
+	var back []UserWithTags
+
+	err = mapstostructs.ColumnsToStructs(columns, &back, "alias")
+
+	if assert.Nil(t, err, "the output should convert back") {
+		assert.Equal(t, []UserWithTags{*users[0], {}}, back, "the round trip should preserve values")
+	}
+
+	_, err = mapstostructs.StructsToColumns([]string{"x"})
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid input") {
+		assert.Equal(t, "the input argument must be a slice or an array of struct but a slice of string was given", err.Error())
+	}
+}
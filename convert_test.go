@@ -5,6 +5,7 @@ import (
 	"math"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -391,3 +392,67 @@ func TestConverUnsupportedType(t *testing.T) {
 
 	assert.False(t, ok)
 }
+
+func TestConvertHex(t *testing.T) {
+	val, ok := convertToType(reflect.ValueOf("0x1F"), reflect.TypeOf(int64(0)), true)
+
+	if assert.True(t, ok) {
+		assert.Equal(t, int64(31), val.Int())
+	}
+
+	val, ok = convertToType(reflect.ValueOf("0Xff"), reflect.TypeOf(uint8(0)), true)
+
+	if assert.True(t, ok) {
+		assert.Equal(t, uint64(255), val.Uint())
+	}
+
+	_, ok = convertToType(reflect.ValueOf("0x"), reflect.TypeOf(int64(0)), true)
+
+	assert.False(t, ok)
+}
+
+func TestConvertOverflow(t *testing.T) {
+	_, ok := convertToType(reflect.ValueOf("300"), reflect.TypeOf(int8(0)), true)
+
+	assert.False(t, ok)
+
+	_, ok = convertToType(reflect.ValueOf("0x100"), reflect.TypeOf(uint8(0)), true)
+
+	assert.False(t, ok)
+}
+
+func TestConvertBool(t *testing.T) {
+	val, ok := convertToType(reflect.ValueOf("true"), reflect.TypeOf(true), true)
+
+	if assert.True(t, ok) {
+		assert.True(t, val.Bool())
+	}
+
+	_, ok = convertToType(reflect.ValueOf("invalid"), reflect.TypeOf(true), true)
+
+	assert.False(t, ok)
+}
+
+func TestConvertTextUnmarshaler(t *testing.T) {
+	val, ok := convertToType(reflect.ValueOf("2022-05-01T10:00:00Z"), reflect.TypeOf(time.Time{}), true)
+
+	if assert.True(t, ok) {
+		assert.Equal(t, time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC), val.Interface())
+	}
+
+	_, ok = convertToType(reflect.ValueOf("invalid"), reflect.TypeOf(time.Time{}), true)
+
+	assert.False(t, ok)
+}
+
+func TestConvertToStringKey(t *testing.T) {
+	val, ok := convertToType(reflect.ValueOf(int16(-5)), reflect.TypeOf(""), true)
+
+	if assert.True(t, ok) {
+		assert.Equal(t, "-5", val.String())
+	}
+
+	_, ok = convertToType(reflect.ValueOf(int16(-5)), reflect.TypeOf(""), false)
+
+	assert.False(t, ok, "numbers should only be formatted as strings for map keys")
+}
@@ -0,0 +1,346 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Decoder holds the settings for a conversion. The package-level functions use a Decoder with only the Tags set, and
+// the Decoder methods of the same names behave identically apart from the additional settings.
+//
+// A Decoder is not modified by its methods and may be used concurrently.
+type Decoder struct {
+	// Tags are the alternative struct tags to use as map keys, in order of preference. The json tag is used if none
+	// of them is present on a field, and the field name if the json tag is not present either.
+	Tags []string
+
+	// Exact disables the implicit conversions otherwise permitted by the reflect library, so that a value must be
+	// assignable to its target type, including for map keys. A float64 will not populate an int and a named type
+	// will not populate a different named type with the same underlying type. Conversions between maps, structs and
+	// slices are still performed element by element, and registered enum names are still accepted.
+	Exact bool
+
+	// ErrorUnmapped causes an error naming the exported fields of any struct being populated for which the input has
+	// no key. For StructToStruct and StructsToStructs these are the destination fields not matched by a source field.
+	ErrorUnmapped bool
+
+	// IndexMapsToSlices allows a map keyed by integers, or by strings of integers such as {"0": "a", "1": "b"}, to
+	// populate a slice or an array, with each value at the index given by its key.
+	IndexMapsToSlices bool
+
+	// IndexGaps sets how missing indexes are handled when IndexMapsToSlices is set. By default the elements at
+	// missing indexes are left as zero values.
+	IndexGaps IndexGapPolicy
+
+	// MaxIndexGaps limits the number of missing indexes left as zero values in a map populating a slice, so that a
+	// sparse map such as {"100000000": "a"} is an error rather than an allocation of its largest index. If it is
+	// zero, up to 1000 indexes may be missing.
+	MaxIndexGaps int
+
+	// SlicesToIndexMaps causes slices and arrays to be output as maps keyed by their indexes as strings by
+	// StructToMap, and allows them to populate maps keyed by integers or strings.
+	SlicesToIndexMaps bool
+
+	// Separator, if set, causes map keys containing it to be interpreted as paths into nested structs, maps, slices
+	// and pointers, so that for example "location.city" populates the City field of the Location field and
+	// "orders.0.id" the ID field of the first element of the Orders field. It implies IndexMapsToSlices.
+	Separator string
+
+	// KeyField is the map key of the field whose value keys each row in MapsToKeyedMap. If it is empty, the struct
+	// field with a key tag is used.
+	KeyField string
+
+	// DuplicateKeys sets how rows with the same key are handled by MapsToKeyedMap. By default they are an error.
+	DuplicateKeys DuplicateKeyPolicy
+
+	// DefaultTag is the name of the struct tag giving the default value of a field for which the input has no key,
+	// such as default:"10s". It is "default" if empty.
+	DefaultTag string
+
+	// DefaultOnNil causes defaults also to be used for keys with nil values.
+	DefaultOnNil bool
+
+	// Setters causes a key X matching no exported field to be passed to a SetX method of the struct, if there is
+	// one, with the value converted to the type of its parameter. StructToMap then also outputs the values returned
+	// by GetX methods, and by X methods where there is a SetX method, for keys X not given by exported fields.
+	Setters bool
+
+	// AllowFields, if set, restricts the fields which may be populated to those with the given paths and the fields
+	// within them. A path is made of the map keys of the fields, separated by dots, such as "profile.name", and is
+	// matched regardless of case. Paths continue through the keys of maps, including the keys kept by a remain field.
+	AllowFields []string
+
+	// DenyFields prevents the fields with the given paths, and the fields within them, from being populated. Paths are
+	// as for AllowFields. Fields with the readonly option in any of their tags, such as json:"id,readonly", are never
+	// populated.
+	DenyFields []string
+
+	// ProtectedKeys sets how keys for fields which may not be populated are handled. By default they are ignored.
+	ProtectedKeys ProtectedKeyPolicy
+
+	// Groups are the active groups for fields with a groups tag, such as groups:"admin,internal". Fields outside the
+	// active groups are ignored when populating structs and omitted by StructToMap. If Groups is empty, all fields
+	// are active.
+	Groups []string
+
+	// path is the path of the struct being populated, for AllowFields and DenyFields.
+	path string
+}
+
+// NewDecoder returns a Decoder using the given alternative struct tags as map keys.
+func NewDecoder(tags ...string) *Decoder {
+	return &Decoder{Tags: tags}
+}
+
+// MapsToStructs is as the package-level MapsToStructs, using the settings of the Decoder.
+func (d *Decoder) MapsToStructs(input []map[string]interface{}, receiver interface{}) error {
+	if err := checkStructSliceReceiver(receiver); err != nil {
+		return err
+	}
+
+	return d.setSlice(reflect.ValueOf(receiver).Elem(), reflect.ValueOf(input))
+}
+
+// MapToStruct is as the package-level MapToStruct, using the settings of the Decoder.
+func (d *Decoder) MapToStruct(input map[string]interface{}, receiver interface{}) error {
+	if err := checkStructReceiver(receiver); err != nil {
+		return err
+	}
+
+	return d.setStructFromMap(reflect.ValueOf(receiver).Elem(), reflect.ValueOf(input))
+}
+
+// TuplesToStructs is as the package-level TuplesToStructs, using the settings of the Decoder.
+func (d *Decoder) TuplesToStructs(input [][]interface{}, receiver interface{}) error {
+	if err := checkStructSliceReceiver(receiver); err != nil {
+		return err
+	}
+
+	return d.setSlice(reflect.ValueOf(receiver).Elem(), reflect.ValueOf(input))
+}
+
+// SliceToSlice is as the package-level SliceToSlice, using the settings of the Decoder.
+func (d *Decoder) SliceToSlice(input interface{}, receiver interface{}) error {
+	if reflect.ValueOf(receiver).Kind() != reflect.Ptr {
+		return fmt.Errorf(notSliceReceiverMsg, reflect.ValueOf(receiver).Kind().String())
+	}
+	sliceValue := reflect.Indirect(reflect.ValueOf(receiver))
+	if sliceValue.Kind() != reflect.Slice {
+		return fmt.Errorf(notSliceReceiverMsg, "ptr to a "+sliceValue.Kind().String())
+	}
+	inputValue := reflect.ValueOf(input)
+	if inputValue.Kind() != reflect.Slice && inputValue.Kind() != reflect.Array {
+		return fmt.Errorf(notSliceInputMsg, inputValue.Kind().String())
+	}
+
+	return d.setSlice(reflect.ValueOf(receiver).Elem(), inputValue)
+}
+
+// MapsToKeyedMap is as the package-level MapsToKeyedMap, using the settings of the Decoder.
+func (d *Decoder) MapsToKeyedMap(input interface{}, receiver interface{}) error {
+	if reflect.ValueOf(receiver).Kind() != reflect.Ptr {
+		return fmt.Errorf(notMapReceiverMsg, reflect.ValueOf(receiver).Kind().String())
+	}
+	mapValue := reflect.Indirect(reflect.ValueOf(receiver))
+	if mapValue.Kind() != reflect.Map {
+		return fmt.Errorf(notMapReceiverMsg, "ptr to a "+mapValue.Kind().String())
+	}
+	inputValue := reflect.ValueOf(input)
+	if inputValue.Kind() != reflect.Slice && inputValue.Kind() != reflect.Array {
+		return fmt.Errorf(notSliceInputMsg, inputValue.Kind().String())
+	}
+
+	return d.setKeyedMap(reflect.ValueOf(receiver).Elem(), inputValue)
+}
+
+// ColumnsToStructs is as the package-level ColumnsToStructs, using the settings of the Decoder.
+func (d *Decoder) ColumnsToStructs(input interface{}, receiver interface{}) error {
+	inputValue := reflect.ValueOf(input)
+	if inputValue.Kind() != reflect.Map {
+		return fmt.Errorf(notColumnsInputMsg, inputValue.Kind().String())
+	}
+	if err := checkStructSliceReceiver(receiver); err != nil {
+		return err
+	}
+	rows, err := columnsToRows(inputValue)
+	if err != nil {
+		return err
+	}
+
+	return d.setSlice(reflect.ValueOf(receiver).Elem(), reflect.ValueOf(rows))
+}
+
+// StructsToColumns is as the package-level StructsToColumns, using the settings of the Decoder.
+func (d *Decoder) StructsToColumns(input interface{}) (map[string]interface{}, error) {
+	inputValue := reflect.ValueOf(input)
+	if inputValue.Kind() != reflect.Slice && inputValue.Kind() != reflect.Array {
+		return nil, fmt.Errorf(notStructSliceInputMsg, inputValue.Kind().String())
+	}
+	structType := inputValue.Type().Elem()
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(notStructSliceInputMsg, inputValue.Kind().String()+" of "+structType.Kind().String())
+	}
+
+	return d.rowsToColumns(inputValue, structType)
+}
+
+// MapToMap is as the package-level MapToMap, using the settings of the Decoder.
+func (d *Decoder) MapToMap(input interface{}, receiver interface{}) error {
+	if reflect.ValueOf(receiver).Kind() != reflect.Ptr {
+		return fmt.Errorf(notMapReceiverMsg, reflect.ValueOf(receiver).Kind().String())
+	}
+	mapValue := reflect.Indirect(reflect.ValueOf(receiver))
+	if mapValue.Kind() != reflect.Map {
+		return fmt.Errorf(notMapReceiverMsg, "ptr to a "+mapValue.Kind().String())
+	}
+	inputValue := reflect.ValueOf(input)
+	if inputValue.Kind() != reflect.Map {
+		return fmt.Errorf(notMapInputMsg, inputValue.Type().String())
+	}
+
+	return d.setMap(reflect.ValueOf(receiver).Elem(), inputValue)
+}
+
+// StructToMap is as the package-level StructToMap, using the settings of the Decoder.
+func (d *Decoder) StructToMap(input interface{}) (map[string]interface{}, error) {
+	inputValue, err := structInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.structToMap(inputValue)
+}
+
+// StructToTuple is as the package-level StructToTuple, using the settings of the Decoder.
+func (d *Decoder) StructToTuple(input interface{}) ([]interface{}, error) {
+	inputValue, err := structInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.structToTuple(inputValue)
+}
+
+// StructToStruct is as the package-level StructToStruct, using the settings of the Decoder.
+func (d *Decoder) StructToStruct(input interface{}, receiver interface{}) error {
+	inputValue, err := structInput(input)
+	if err != nil {
+		return err
+	}
+	if err := checkStructReceiver(receiver); err != nil {
+		return err
+	}
+	output, err := d.structToMap(inputValue)
+	if err != nil {
+		return err
+	}
+
+	return d.setStructFromMap(reflect.ValueOf(receiver).Elem(), reflect.ValueOf(output))
+}
+
+// StructsToStructs is as the package-level StructsToStructs, using the settings of the Decoder.
+func (d *Decoder) StructsToStructs(input interface{}, receiver interface{}) error {
+	inputValue := reflect.ValueOf(input)
+	if inputValue.Kind() != reflect.Slice && inputValue.Kind() != reflect.Array {
+		return fmt.Errorf(notStructSliceInputMsg, inputValue.Kind().String())
+	}
+	elementType := inputValue.Type().Elem()
+	for elementType.Kind() == reflect.Ptr {
+		elementType = elementType.Elem()
+	}
+	if elementType.Kind() != reflect.Struct {
+		return fmt.Errorf(notStructSliceInputMsg, inputValue.Kind().String()+" of "+elementType.Kind().String())
+	}
+	if err := checkStructSliceReceiver(receiver); err != nil {
+		return err
+	}
+	outputs := make([]map[string]interface{}, inputValue.Len())
+	for i := range outputs {
+		element := inputValue.Index(i)
+		for element.Kind() == reflect.Ptr && !element.IsNil() {
+			element = element.Elem()
+		}
+		if element.Kind() == reflect.Ptr {
+			continue
+		}
+		output, err := d.structToMap(element)
+		if err != nil {
+			return fmt.Errorf("%s"+rowSuffix, err, i+1)
+		}
+		outputs[i] = output
+	}
+
+	return d.setSlice(reflect.ValueOf(receiver).Elem(), reflect.ValueOf(outputs))
+}
+
+// Convert is as the package-level Convert, using the settings of the Decoder.
+func (d *Decoder) Convert(input interface{}, receiver interface{}) error {
+	receiverValue := reflect.ValueOf(receiver)
+	if receiverValue.Kind() != reflect.Ptr {
+		return fmt.Errorf(notPtrReceiverMsg, receiverValue.Kind().String())
+	}
+	if receiverValue.IsNil() {
+		return fmt.Errorf(notPtrReceiverMsg, "nil ptr")
+	}
+
+	inputValue := reflect.ValueOf(input)
+	// A top-level slice populates a map by index without SlicesToIndexMaps, which only extends this to nested values.
+	wantType := receiverValue.Elem().Type()
+	for wantType.Kind() == reflect.Ptr {
+		wantType = wantType.Elem()
+	}
+	if wantType.Kind() == reflect.Map && (inputValue.Kind() == reflect.Slice || inputValue.Kind() == reflect.Array) {
+		inputValue = indexMap(inputValue)
+	}
+
+	return d.setRecursively(receiverValue.Elem(), inputValue)
+}
+
+func checkStructSliceReceiver(receiver interface{}) error {
+	if reflect.ValueOf(receiver).Kind() != reflect.Ptr {
+		return fmt.Errorf(notStructSliceReceiverMsg, reflect.ValueOf(receiver).Kind().String())
+	}
+	structValues := reflect.Indirect(reflect.ValueOf(receiver))
+	if structValues.Kind() != reflect.Slice {
+		return fmt.Errorf(notStructSliceReceiverMsg, "ptr to a "+structValues.Kind().String())
+	}
+	structType := structValues.Type().Elem()
+	elementKind := "ptr to a slice of "
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+		elementKind += "ptr to a "
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf(notStructSliceReceiverMsg, elementKind+structType.Kind().String())
+	}
+	return nil
+}
+
+func checkStructReceiver(receiver interface{}) error {
+	if reflect.ValueOf(receiver).Kind() != reflect.Ptr {
+		return fmt.Errorf(notStructReceiverMsg, reflect.ValueOf(receiver).Kind().String())
+	}
+	structType := reflect.Indirect(reflect.ValueOf(receiver)).Type()
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf(notStructReceiverMsg, "ptr to a "+structType.Kind().String())
+	}
+	return nil
+}
+
+// structInput returns the struct value of a struct or pointer to struct input.
+func structInput(input interface{}) (reflect.Value, error) {
+	inputValue := reflect.ValueOf(input)
+	if inputValue.Kind() == reflect.Ptr {
+		inputValue = inputValue.Elem()
+		if inputValue.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf(notStructInputMsg, "ptr to a "+inputValue.Kind().String())
+		}
+	}
+	if inputValue.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf(notStructInputMsg, inputValue.Kind().String())
+	}
+	return inputValue, nil
+}
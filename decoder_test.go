@@ -0,0 +1,95 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type UserID int
+
+type OrderID int
+
+type Order struct {
+	ID     OrderID `json:"id"`
+	UserID UserID  `json:"userId"`
+	Total  float32 `json:"total"`
+	Lines  []int   `json:"lines"`
+}
+
+func TestDecoderTags(t *testing.T) {
+	maps := []map[string]interface{}{
+		{"id": 213, "name": "Zhaoliu", "sex": "male", "age": 19},
+	}
+
+	var users []UserWithTags
+
+	err := mapstostructs.NewDecoder("alias").MapsToStructs(maps, &users)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, "male", users[0].Gender, "values should be correctly set from tags")
+	}
+}
+
+func TestDecoderExact(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Exact: true}
+
+	var order Order
+
+	err := decoder.MapToStruct(map[string]interface{}{
+		"id":     OrderID(1),
+		"userId": UserID(2),
+		"total":  float32(9.5),
+		"lines":  []interface{}{1, 2},
+	}, &order)
+
+	if assert.Nil(t, err, "error should be nil for assignable values") {
+		assert.Equal(t, Order{ID: 1, UserID: 2, Total: 9.5, Lines: []int{1, 2}}, order, "values should be correctly set")
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"id": float64(1)}, &order)
+
+	if assert.NotNil(t, err, "error should not be nil for a float64 into an int type") {
+		expected := "the ID field for a struct of type Order must be assignable to mapstostructs_test.OrderID type without conversion, but received float64 type '1'"
+		assert.Equal(t, expected, err.Error(), "the error string should name both types")
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"id": UserID(1)}, &order)
+
+	if assert.NotNil(t, err, "error should not be nil between named types") {
+		expected := "the ID field for a struct of type Order must be assignable to mapstostructs_test.OrderID type without conversion, but received mapstostructs_test.UserID type '1'"
+		assert.Equal(t, expected, err.Error(), "the error string should name both types")
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"total": 9}, &order)
+
+	if assert.NotNil(t, err, "error should not be nil for an int into a float32") {
+		expected := "the Total field for a struct of type Order must be assignable to float32 type without conversion, but received int type '9'"
+		assert.Equal(t, expected, err.Error(), "the error string should name both types")
+	}
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"id": UserID(1), "total": 9}, &order)
+
+	assert.Nil(t, err, "conversions should be permitted outside exact mode")
+}
+
+func TestDecoderExactMapKeys(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Exact: true}
+
+	var receiver map[int]string
+
+	err := decoder.MapToMap(map[int]string{5: "test"}, &receiver)
+
+	if assert.Nil(t, err, "error should be nil for assignable keys") {
+		assert.Equal(t, "test", receiver[5])
+	}
+
+	err = decoder.MapToMap(map[string]interface{}{"5": "test"}, &receiver)
+
+	if assert.NotNil(t, err, "error should not be nil for parsed keys") {
+		expected := "the map key for a map[int]string must be assignable to int type without conversion, but received string type '5'"
+		assert.Equal(t, expected, err.Error(), "the error string should name both types")
+	}
+}
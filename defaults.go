@@ -0,0 +1,174 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTag    = "default"
+	badDefaultMsg = "the default '%s' for the %s field for a struct of type %s cannot be parsed as %s type"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	defaultsCache sync.Map
+)
+
+// fieldDefault is the default value of a struct field, or else the defaults of a nested struct.
+type fieldDefault struct {
+	index  int
+	value  reflect.Value
+	nested []fieldDefault
+}
+
+type defaultsKey struct {
+	structType reflect.Type
+	tagName    string
+}
+
+type defaultsEntry struct {
+	defaults []fieldDefault
+	err      error
+}
+
+// defaults returns the defaults of a struct type, parsing them when the type is first used.
+func (d *Decoder) defaults(structType reflect.Type) ([]fieldDefault, error) {
+	tagName := d.DefaultTag
+	if tagName == "" {
+		tagName = defaultTag
+	}
+	key := defaultsKey{structType: structType, tagName: tagName}
+	if entry, ok := defaultsCache.Load(key); ok {
+		return entry.(defaultsEntry).defaults, entry.(defaultsEntry).err
+	}
+	defaults, err := parseDefaults(structType, tagName)
+	defaultsCache.Store(key, defaultsEntry{defaults: defaults, err: err})
+	return defaults, err
+}
+
+// parseDefaults parses the defaults of the exported fields of a struct type with the tag, and of its nested structs.
+func parseDefaults(structType reflect.Type, tagName string) ([]fieldDefault, error) {
+	var defaults []fieldDefault
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if tag, ok := field.Tag.Lookup(tagName); ok {
+			value, ok := parseDefault(tag, field.Type)
+			if !ok {
+				return nil, fmt.Errorf(badDefaultMsg, tag, field.Name, structType.Name(), field.Type.String())
+			}
+			defaults = append(defaults, fieldDefault{index: i, value: value})
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			nested, err := parseDefaults(field.Type, tagName)
+			if err != nil {
+				return nil, err
+			}
+			if nested != nil {
+				defaults = append(defaults, fieldDefault{index: i, nested: nested})
+			}
+		}
+	}
+	return defaults, nil
+}
+
+// parseDefault parses a default value into a type as a duration, a registered enum name, a string, a type
+// implementing encoding.TextUnmarshaler such as time.Time, a bool or a number, or a comma-separated slice of these.
+func parseDefault(tag string, wantType reflect.Type) (reflect.Value, bool) {
+	switch {
+
+	case wantType.Kind() == reflect.Ptr:
+		value, ok := parseDefault(tag, wantType.Elem())
+		if !ok {
+			return reflect.Value{}, false
+		}
+		ptr := reflect.New(wantType.Elem())
+		ptr.Elem().Set(value)
+		return ptr, true
+
+	case wantType == durationType:
+		duration, err := time.ParseDuration(tag)
+		return reflect.ValueOf(duration), err == nil
+	}
+
+	if value, handled, err := convertEnum(reflect.ValueOf(tag), wantType); handled {
+		return value, err == nil
+	}
+	// A slice is split on commas rather than converted, which would give the bytes or runes of the tag for a slice
+	// such as []byte or []int32, unless it parses itself as text, such as net.IP.
+	if wantType.Kind() != reflect.Slice {
+		return convertToType(reflect.ValueOf(tag), wantType, true)
+	}
+	if reflect.PtrTo(wantType).Implements(textUnmarshalerType) {
+		return parseString(tag, wantType)
+	}
+	var parts []string
+	if tag != "" {
+		parts = strings.Split(tag, ",")
+	}
+	slice := reflect.MakeSlice(wantType, len(parts), len(parts))
+	for i, part := range parts {
+		value, ok := parseDefault(strings.TrimSpace(part), wantType.Elem())
+		if !ok {
+			return reflect.Value{}, false
+		}
+		slice.Index(i).Set(value)
+	}
+	return slice, true
+}
+
+// setDefaults sets the defaults of the fields of a struct which are not in the mapped set, or are in the nulls set,
+// and marks them as mapped.
+func setDefaults(structValue reflect.Value, defaults []fieldDefault, mapped map[string]bool, nulls map[string]bool) {
+	for _, fieldDefault := range defaults {
+		name := structValue.Type().Field(fieldDefault.index).Name
+		if mapped[name] && !nulls[name] {
+			continue
+		}
+		if fieldDefault.nested != nil {
+			setDefaults(structValue.Field(fieldDefault.index), fieldDefault.nested, map[string]bool{}, nil)
+			continue
+		}
+		// Copy the default so that a slice or a pointer is not shared between structs.
+		setCopy(structValue.Field(fieldDefault.index), fieldDefault.value)
+		mapped[name] = true
+	}
+}
+
+// setCopy sets a value which may be a slice or a pointer to a copy of it.
+func setCopy(receiver reflect.Value, value reflect.Value) {
+	switch value.Kind() {
+
+	case reflect.Ptr:
+		ptr := reflect.New(value.Type().Elem())
+		setCopy(ptr.Elem(), value.Elem())
+		receiver.Set(ptr)
+
+	case reflect.Slice:
+		slice := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			setCopy(slice.Index(i), value.Index(i))
+		}
+		receiver.Set(slice)
+
+	default:
+		receiver.Set(value)
+	}
+}
+
+// isNil reports whether a value is nil once any interfaces are unwrapped.
+func isNil(value reflect.Value) bool {
+	for value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	return !value.IsValid() || (value.Kind() == reflect.Ptr || value.Kind() == reflect.Map ||
+		value.Kind() == reflect.Slice) && value.IsNil()
+}
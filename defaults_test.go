@@ -0,0 +1,135 @@
+package mapstostructs_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Retry struct {
+	Attempts int           `json:"attempts" default:"3"`
+	Backoff  time.Duration `json:"backoff" default:"1.5s"`
+}
+
+type Settings struct {
+	Name    string    `json:"name" default:"anonymous"`
+	Enabled *bool     `json:"enabled" default:"true"`
+	Ratio   float32   `json:"ratio" default:"0.5"`
+	Since   time.Time `json:"since" default:"2020-01-02T03:04:05Z"`
+	Hosts   []string  `json:"hosts" default:"a, b"`
+	Ports   []uint16  `json:"ports" default:"80,0x1bb"`
+	Retry   Retry     `json:"retry"`
+	Status  Status    `json:"status" default:"active"`
+	Other   string    `json:"other" conf:"other"`
+}
+
+type BadDefault struct {
+	Limit int `json:"limit" default:"lots"`
+}
+
+func TestDefaultTag(t *testing.T) {
+	var settings Settings
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"name": "x", "retry": map[string]interface{}{"attempts": 5}}, &settings)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		enabled := true
+		assert.Equal(t, Settings{
+			Name:    "x",
+			Enabled: &enabled,
+			Ratio:   0.5,
+			Since:   time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			Hosts:   []string{"a", "b"},
+			Ports:   []uint16{80, 443},
+			Retry:   Retry{Attempts: 5, Backoff: 1500 * time.Millisecond},
+			Status:  StatusActive,
+		}, settings, "defaults should be set for missing keys")
+	}
+}
+
+func TestDefaultTagEmptyInput(t *testing.T) {
+	var settings Settings
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{}, &settings)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, "anonymous", settings.Name, "defaults should be set for empty input")
+		assert.Equal(t, Retry{Attempts: 3, Backoff: 1500 * time.Millisecond}, settings.Retry, "defaults should be set in missing nested structs")
+	}
+}
+
+func TestDefaultTagNilValues(t *testing.T) {
+	var settings Settings
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"name": nil, "retry": nil}, &settings)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, "", settings.Name, "defaults should not be set for nil values")
+		assert.Equal(t, Retry{}, settings.Retry, "defaults should not be set in nested structs for nil values")
+	}
+}
+
+func TestDefaultOnNil(t *testing.T) {
+	decoder := &mapstostructs.Decoder{DefaultOnNil: true, ErrorUnmapped: true}
+
+	var settings Settings
+
+	err := decoder.MapToStruct(map[string]interface{}{"name": nil, "retry": nil, "other": nil}, &settings)
+
+	if assert.Nil(t, err, "error should be nil with defaults for all missing keys") {
+		assert.Equal(t, "anonymous", settings.Name, "defaults should be set for nil values with DefaultOnNil")
+		assert.Equal(t, Retry{Attempts: 3, Backoff: 1500 * time.Millisecond}, settings.Retry, "defaults should be set in nested structs for nil values with DefaultOnNil")
+	}
+}
+
+func TestDefaultTagSetting(t *testing.T) {
+	decoder := &mapstostructs.Decoder{DefaultTag: "conf"}
+
+	var settings Settings
+
+	err := decoder.MapToStruct(map[string]interface{}{"name": "x"}, &settings)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Settings{Name: "x", Other: "other"}, settings, "only the configured tag should give defaults")
+	}
+}
+
+func TestDefaultTagBadDefault(t *testing.T) {
+	var bad BadDefault
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"limit": 1}, &bad)
+
+	if assert.NotNil(t, err, "error should not be nil with an unparsable default") {
+		assert.Equal(t, "the default 'lots' for the Limit field for a struct of type BadDefault cannot be parsed as int type", err.Error())
+	}
+
+	var bads []BadDefault
+
+	err = mapstostructs.MapsToStructs([]map[string]interface{}{{}}, &bads)
+
+	if assert.NotNil(t, err, "error should not be nil with an unparsable default") {
+		assert.Equal(t, "the default 'lots' for the Limit field for a struct of type BadDefault cannot be parsed as int type in row 1", err.Error())
+	}
+}
+
+func TestDefaultSlices(t *testing.T) {
+	type Limits struct {
+		Codes []int32 `json:"codes" default:"1,2"`
+		Flags []byte  `json:"flags" default:"1, 2"`
+		Host  net.IP  `json:"host" default:"127.0.0.1"`
+	}
+
+	var limits Limits
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{}, &limits)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []int32{1, 2}, limits.Codes, "slices of runes should be split on commas")
+		assert.Equal(t, []byte{1, 2}, limits.Flags, "slices of bytes should be split on commas")
+		assert.Equal(t, net.IPv4(127, 0, 0, 1), limits.Host, "slices parsing themselves as text should not be split")
+	}
+}
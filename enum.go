@@ -0,0 +1,166 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	badEnumMsg        = "must be a valid %s name (%s), but received '%v'"
+	nilEnumMsg        = "the enum argument must not be nil"
+	badEnumValueMsg   = "the value for the enum name '%s' must be or be convertible to %s type, but received '%v'"
+	notIntegerEnumMsg = "the enum argument must be of an integer type but a %s was given"
+	badEnumRangeMsg   = "the enum range minimum %d must not be greater than the maximum %d"
+	bigEnumRangeMsg   = "the enum range %d to %d must not have more than %d values"
+	enumOverflowMsg   = "the enum range %d to %d must be within the values of %s type"
+	notComparableMsg  = "the enum argument must be of a comparable type but a %s was given"
+
+	// maxEnumRange is the largest number of values RegisterEnumStringer will name.
+	maxEnumRange = 1 << 16
+)
+
+type enumTable struct {
+	byName  map[string]reflect.Value
+	byValue map[interface{}]string
+	names   string
+}
+
+var (
+	enumMutex  sync.RWMutex
+	enumTables = make(map[reflect.Type]*enumTable)
+)
+
+// RegisterEnum registers a table of names for the type of the enum argument, so that string values in an input map
+// can populate fields of that type and StructToMap can emit the names in place of the underlying values.
+//
+// Each value in the names map must be or be convertible to the enum type. Registering a type again replaces its
+// previous table.
+//
+// Names are case-sensitive. A name which is not in the table causes a conversion error listing the valid names.
+func RegisterEnum(enum interface{}, names map[string]interface{}) error {
+	if enum == nil {
+		return fmt.Errorf(nilEnumMsg)
+	}
+	enumType := reflect.TypeOf(enum)
+	if !enumType.Comparable() {
+		return fmt.Errorf(notComparableMsg, enumType.String())
+	}
+	table := &enumTable{
+		byName:  make(map[string]reflect.Value, len(names)),
+		byValue: make(map[interface{}]string, len(names)),
+	}
+	for name, value := range names {
+		converted, ok := convertToType(reflect.ValueOf(value), enumType, false)
+		if !ok {
+			return fmt.Errorf(badEnumValueMsg, name, enumType.String(), value)
+		}
+		table.add(name, converted)
+	}
+	storeEnumTable(enumType, table)
+	return nil
+}
+
+// RegisterEnumStringer registers a table of names for the type of the enum argument, deriving the names by calling
+// String() on each value from min to max inclusive. The enum type must be of an integer kind, the range must be within
+// its values and it may have at most 65536 values.
+//
+// See RegisterEnum for how the names are used.
+func RegisterEnumStringer(enum fmt.Stringer, min, max int64) error {
+	if enum == nil {
+		return fmt.Errorf(nilEnumMsg)
+	}
+	if min > max {
+		return fmt.Errorf(badEnumRangeMsg, min, max)
+	}
+	if uint64(max-min) >= maxEnumRange {
+		return fmt.Errorf(bigEnumRangeMsg, min, max, maxEnumRange)
+	}
+	enumType := reflect.TypeOf(enum)
+	value := reflect.New(enumType).Elem()
+	switch enumType.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		if value.OverflowInt(min) || value.OverflowInt(max) {
+			return fmt.Errorf(enumOverflowMsg, min, max, enumType.String())
+		}
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		if min < 0 || value.OverflowUint(uint64(max)) {
+			return fmt.Errorf(enumOverflowMsg, min, max, enumType.String())
+		}
+	default:
+		return fmt.Errorf(notIntegerEnumMsg, enumType.String())
+	}
+	table := &enumTable{
+		byName:  make(map[string]reflect.Value),
+		byValue: make(map[interface{}]string),
+	}
+	for i := min; ; i++ {
+		value := reflect.New(enumType).Elem()
+		if enumType.Kind() >= reflect.Uint && enumType.Kind() <= reflect.Uint64 {
+			value.SetUint(uint64(i))
+		} else {
+			value.SetInt(i)
+		}
+		table.add(value.Interface().(fmt.Stringer).String(), value)
+		if i == max {
+			break
+		}
+	}
+	storeEnumTable(enumType, table)
+	return nil
+}
+
+func (t *enumTable) add(name string, value reflect.Value) {
+	t.byName[name] = value
+	if _, ok := t.byValue[value.Interface()]; !ok {
+		t.byValue[value.Interface()] = name
+	}
+}
+
+func storeEnumTable(enumType reflect.Type, table *enumTable) {
+	quoted := make([]string, 0, len(table.byName))
+	for name := range table.byName {
+		quoted = append(quoted, "'"+name+"'")
+	}
+	sort.Strings(quoted)
+	table.names = strings.Join(quoted, ", ")
+
+	enumMutex.Lock()
+	defer enumMutex.Unlock()
+	enumTables[enumType] = table
+}
+
+func lookupEnum(enumType reflect.Type) *enumTable {
+	enumMutex.RLock()
+	defer enumMutex.RUnlock()
+	return enumTables[enumType]
+}
+
+// convertEnum converts a string input to a registered enum type. The handled result is false when the wanted type is
+// not a registered enum or the input is not a string, leaving the caller to try other conversions.
+func convertEnum(input reflect.Value, wantType reflect.Type) (converted reflect.Value, handled bool, err error) {
+	if input.Kind() != reflect.String {
+		return reflect.Value{}, false, nil
+	}
+	table := lookupEnum(wantType)
+	if table == nil {
+		return reflect.Value{}, false, nil
+	}
+	converted, ok := table.byName[input.String()]
+	if !ok {
+		return reflect.Value{}, true, fmt.Errorf(badEnumMsg, wantType.String(), table.names, input.Interface())
+	}
+	return converted, true, nil
+}
+
+// enumName returns the registered name for an enum value, if there is one.
+func enumName(input reflect.Value) (string, bool) {
+	table := lookupEnum(input.Type())
+	if table == nil {
+		return "", false
+	}
+	name, ok := table.byValue[input.Interface()]
+	return name, ok
+}
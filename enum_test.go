@@ -0,0 +1,199 @@
+package mapstostructs_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Status int
+
+const (
+	StatusUnknown Status = iota
+	StatusActive
+	StatusSuspended
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusActive:
+		return "active"
+	case StatusSuspended:
+		return "suspended"
+	}
+	return "unknown"
+}
+
+type Colour uint8
+
+const (
+	Red Colour = iota + 1
+	Green
+)
+
+type Account struct {
+	ID      int            `json:"id"`
+	Status  Status         `json:"status"`
+	Colour  *Colour        `json:"colour"`
+	History []Status       `json:"history"`
+	Counts  map[Status]int `json:"counts"`
+	Labels  map[int]Colour `json:"labels"`
+}
+
+func init() {
+	_ = mapstostructs.RegisterEnumStringer(StatusUnknown, int64(StatusUnknown), int64(StatusSuspended))
+	_ = mapstostructs.RegisterEnum(Colour(0), map[string]interface{}{"red": Red, "green": 2})
+}
+
+func TestEnumFromNames(t *testing.T) {
+	input := map[string]interface{}{
+		"id":      1,
+		"status":  "suspended",
+		"colour":  "green",
+		"history": []interface{}{"active", "suspended"},
+		"counts":  map[string]interface{}{"active": 3},
+		"labels":  map[string]interface{}{"7": "red"},
+	}
+
+	var account Account
+
+	err := mapstostructs.MapToStruct(input, &account)
+
+	if assert.Nil(t, err, "error should be nil for valid enum names") {
+		assert.Equal(t, StatusSuspended, account.Status, "enum names should be converted")
+		if assert.NotNil(t, account.Colour, "pointers to enums should be set") {
+			assert.Equal(t, Green, *account.Colour, "enum names should be converted through pointers")
+		}
+		assert.Equal(t, []Status{StatusActive, StatusSuspended}, account.History, "enum names in slices should be converted")
+		assert.Equal(t, 3, account.Counts[StatusActive], "enum names should be converted as map keys")
+		assert.Equal(t, Red, account.Labels[7], "enum names should be converted as map values")
+	}
+}
+
+func TestEnumFromValues(t *testing.T) {
+	input := map[string]interface{}{"status": float64(2), "colour": 1}
+
+	var account Account
+
+	err := mapstostructs.MapToStruct(input, &account)
+
+	if assert.Nil(t, err, "error should be nil for numeric enum values") {
+		assert.Equal(t, StatusSuspended, account.Status, "numeric values should still be converted")
+		assert.Equal(t, Red, *account.Colour, "numeric values should still be converted")
+	}
+}
+
+func TestEnumBadName(t *testing.T) {
+	input := []map[string]interface{}{
+		{"id": 1, "status": "active"},
+		{"id": 2, "status": "deleted"},
+	}
+
+	var accounts []Account
+
+	err := mapstostructs.MapsToStructs(input, &accounts)
+
+	if assert.NotNil(t, err, "error should not be nil for an unknown enum name") {
+		expected := "the Status field for a struct of type Account must be a valid mapstostructs_test.Status name ('active', 'suspended', 'unknown'), but received 'deleted' in row 2"
+		assert.Equal(t, expected, err.Error(), "the error string should list the valid names")
+	}
+
+	var counts map[Status]int
+
+	err = mapstostructs.MapToMap(map[string]int{"deleted": 1}, &counts)
+
+	if assert.NotNil(t, err, "error should not be nil for an unknown enum name as a map key") {
+		expected := "the map key for a map[mapstostructs_test.Status]int must be a valid mapstostructs_test.Status name ('active', 'suspended', 'unknown'), but received 'deleted'"
+		assert.Equal(t, expected, err.Error(), "the error string should list the valid names")
+	}
+}
+
+func TestEnumToNames(t *testing.T) {
+	green := Green
+	account := Account{
+		ID:      1,
+		Status:  StatusActive,
+		Colour:  &green,
+		History: []Status{StatusSuspended},
+		Counts:  map[Status]int{StatusSuspended: 4},
+		Labels:  map[int]Colour{9: Colour(99)},
+	}
+
+	output, err := mapstostructs.StructToMap(account)
+
+	if assert.Nil(t, err, "error should be nil for a valid struct") {
+		assert.Equal(t, "active", output["status"], "enum values should be emitted as names")
+		assert.Equal(t, "green", output["colour"], "enum values should be emitted as names through pointers")
+		assert.Equal(t, []interface{}{"suspended"}, output["history"], "enum values in slices should be emitted as names")
+		assert.Equal(t, map[string]interface{}{"suspended": 4}, output["counts"], "enum map keys should be emitted as names")
+		assert.Equal(t, map[string]interface{}{"9": Colour(99)}, output["labels"], "unregistered enum values should be emitted unchanged")
+	}
+}
+
+func TestRegisterEnumErrors(t *testing.T) {
+	err := mapstostructs.RegisterEnum(Colour(0), map[string]interface{}{"blue": "blue"})
+
+	if assert.NotNil(t, err, "error should not be nil for an unconvertible value") {
+		assert.Equal(t, "the value for the enum name 'blue' must be or be convertible to mapstostructs_test.Colour type, but received 'blue'", err.Error())
+	}
+
+	err = mapstostructs.RegisterEnumStringer(StatusActive, 2, 1)
+
+	if assert.NotNil(t, err, "error should not be nil for an inverted range") {
+		assert.Equal(t, "the enum range minimum 2 must not be greater than the maximum 1", err.Error())
+	}
+
+	err = mapstostructs.RegisterEnumStringer(stringStatus("x"), 0, 1)
+
+	if assert.NotNil(t, err, "error should not be nil for a non-integer enum") {
+		assert.Equal(t, "the enum argument must be of an integer type but a mapstostructs_test.stringStatus was given", err.Error())
+	}
+}
+
+type stringStatus string
+
+func (s stringStatus) String() string {
+	return string(s)
+}
+
+type level uint8
+
+func (l level) String() string {
+	return fmt.Sprintf("level%d", uint8(l))
+}
+
+func TestRegisterEnumUncomparable(t *testing.T) {
+	err := mapstostructs.RegisterEnum([]int{}, map[string]interface{}{"one": []int{1}})
+
+	if assert.NotNil(t, err, "error should not be nil for an uncomparable enum") {
+		assert.Equal(t, "the enum argument must be of a comparable type but a []int was given", err.Error())
+	}
+}
+
+func TestRegisterEnumStringerRange(t *testing.T) {
+	err := mapstostructs.RegisterEnumStringer(StatusActive, 0, math.MaxInt64)
+
+	if assert.NotNil(t, err, "error should not be nil for a huge range") {
+		assert.Equal(t, "the enum range 0 to 9223372036854775807 must not have more than 65536 values", err.Error())
+	}
+
+	err = mapstostructs.RegisterEnumStringer(level(0), -1, 1)
+
+	if assert.NotNil(t, err, "error should not be nil for a negative unsigned minimum") {
+		assert.Equal(t, "the enum range -1 to 1 must be within the values of mapstostructs_test.level type", err.Error())
+	}
+
+	err = mapstostructs.RegisterEnumStringer(level(0), 0, 256)
+
+	if assert.NotNil(t, err, "error should not be nil for a range beyond the type") {
+		assert.Equal(t, "the enum range 0 to 256 must be within the values of mapstostructs_test.level type", err.Error())
+	}
+
+	err = mapstostructs.RegisterEnumStringer(level(0), 0, 255)
+
+	assert.Nil(t, err, "error should be nil for the full range of the type")
+}
@@ -0,0 +1,125 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	flattenConflictMsg = "the key '%s' conflicts with the value for the key '%s'"
+	emptySeparatorMsg  = "the separator must not be empty"
+)
+
+// flatten adds the leaves of a tree of maps and slices to the output, keyed by their paths joined by the separator.
+// Empty maps and slices are leaves.
+func flatten(prefix string, input reflect.Value, separator string, output map[string]interface{}) {
+	for input.Kind() == reflect.Interface {
+		input = input.Elem()
+	}
+	if prefix != "" {
+		prefix += separator
+	}
+
+	switch {
+
+	case input.Kind() == reflect.Map && input.Len() > 0:
+		mapRange := input.MapRange()
+		for mapRange.Next() {
+			key, ok := formatMapKey(mapRange.Key())
+			if !ok {
+				key = fmt.Sprint(mapRange.Key().Interface())
+			}
+			flatten(prefix+key, mapRange.Value(), separator, output)
+		}
+
+	case (input.Kind() == reflect.Slice || input.Kind() == reflect.Array) && input.Len() > 0:
+		for i := 0; i < input.Len(); i++ {
+			flatten(prefix+strconv.Itoa(i), input.Index(i), separator, output)
+		}
+
+	default:
+		output[strings.TrimSuffix(prefix, separator)] = describeValue(input)
+	}
+}
+
+// unflatten nests the values of keys containing the separator in maps keyed by the parts of the keys. Values which
+// are already maps are merged into, without modifying them.
+func unflatten(input map[string]interface{}, separator string) (map[string]interface{}, error) {
+	if separator == "" {
+		return nil, fmt.Errorf(emptySeparatorMsg)
+	}
+	keys := make([]string, 0, len(input))
+	for key := range input {
+		keys = append(keys, key)
+	}
+	// Shorter paths first, so that a value is always set before any path passing through it.
+	sort.Slice(keys, func(i, j int) bool {
+		iParts, jParts := strings.Count(keys[i], separator), strings.Count(keys[j], separator)
+		if iParts != jParts {
+			return iParts < jParts
+		}
+		return keys[i] < keys[j]
+	})
+
+	output := make(map[string]interface{}, len(input))
+	owned := map[uintptr]bool{reflect.ValueOf(output).Pointer(): true}
+	for _, key := range keys {
+		parts := strings.Split(key, separator)
+		current := output
+		for i, part := range parts[:len(parts)-1] {
+			next, exists := current[part]
+			if !exists {
+				next = make(map[string]interface{})
+				owned[reflect.ValueOf(next).Pointer()] = true
+				current[part] = next
+			}
+			nested, ok := next.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf(flattenConflictMsg, key, strings.Join(parts[:i+1], separator))
+			}
+			if !owned[reflect.ValueOf(nested).Pointer()] {
+				copied := make(map[string]interface{}, len(nested)+1)
+				for nestedKey, value := range nested {
+					copied[nestedKey] = value
+				}
+				owned[reflect.ValueOf(copied).Pointer()] = true
+				current[part] = copied
+				nested = copied
+			}
+			current = nested
+		}
+		current[parts[len(parts)-1]] = input[key]
+	}
+	return output, nil
+}
+
+// unflattenInput returns the input unflattened as a map[string]interface{} if any of its keys contains the separator,
+// or else unchanged.
+func unflattenInput(input reflect.Value, separator string) (reflect.Value, error) {
+	var nested bool
+	for _, key := range input.MapKeys() {
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		if key.Kind() != reflect.String {
+			return input, nil
+		}
+		nested = nested || strings.Contains(key.String(), separator)
+	}
+	if !nested {
+		return input, nil
+	}
+	flat := make(map[string]interface{}, input.Len())
+	mapRange := input.MapRange()
+	for mapRange.Next() {
+		flat[fmt.Sprint(mapRange.Key().Interface())] = mapRange.Value().Interface()
+	}
+	output, err := unflatten(flat, separator)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(output), nil
+}
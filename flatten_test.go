@@ -0,0 +1,126 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Customer struct {
+	Name     string            `json:"name"`
+	Location *Location         `json:"location"`
+	Orders   []Order           `json:"orders"`
+	Meta     map[string]string `json:"meta"`
+}
+
+func TestSeparator(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Separator: "."}
+	input := map[string]interface{}{
+		"name":           "Zhaoliu",
+		"location.city":  "London",
+		"location":       map[string]interface{}{"country": "UK"},
+		"orders.1.id":    2,
+		"orders.0.id":    1,
+		"orders.0.lines": []int{3},
+		"meta.colour":    "blue",
+	}
+
+	var customer Customer
+
+	err := decoder.MapToStruct(input, &customer)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, "Zhaoliu", customer.Name, "top-level keys should be set")
+		assert.Equal(t, &Location{Country: "UK", City: "London"}, customer.Location, "paths should be merged into maps")
+		assert.Equal(t, []Order{{ID: 1, Lines: []int{3}}, {ID: 2}}, customer.Orders, "paths should walk into slices")
+		assert.Equal(t, map[string]string{"colour": "blue"}, customer.Meta, "paths should walk into maps")
+	}
+	assert.Equal(t, map[string]interface{}{"country": "UK"}, input["location"], "the input should not be modified")
+
+	err = decoder.MapToStruct(map[string]interface{}{"name.first": "x"}, &customer)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the Name field for a struct of type Customer must be or be convertible to string type, but received 'map[first:x]'", err.Error())
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"name": "x", "name.first": "y"}, &customer)
+
+	if assert.NotNil(t, err, "error should not be nil with conflicting keys") {
+		assert.Equal(t, "the key 'name.first' conflicts with the value for the key 'name'", err.Error())
+	}
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"location.city": "Paris"}, &customer)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Nil(t, customer.Location, "dotted keys should be ignored without a separator")
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	input := map[string]interface{}{
+		"name":     "Zhaoliu",
+		"location": map[string]interface{}{"city": "London"},
+		"orders":   []interface{}{map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2}},
+		"empty":    map[string]interface{}{},
+		"scores":   map[int]float64{2022: 1.5},
+	}
+
+	flat := mapstostructs.Flatten(input, ".")
+
+	assert.Equal(t, map[string]interface{}{
+		"name":          "Zhaoliu",
+		"location.city": "London",
+		"orders.0.id":   1,
+		"orders.1.id":   2,
+		"empty":         map[string]interface{}{},
+		"scores.2022":   1.5,
+	}, flat, "leaves should be keyed by their paths")
+
+	nested, err := mapstostructs.Unflatten(flat, ".")
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{
+			"name":     "Zhaoliu",
+			"location": map[string]interface{}{"city": "London"},
+			"orders": map[string]interface{}{
+				"0": map[string]interface{}{"id": 1},
+				"1": map[string]interface{}{"id": 2},
+			},
+			"empty":  map[string]interface{}{},
+			"scores": map[string]interface{}{"2022": 1.5},
+		}, nested, "paths should be nested in maps")
+		assert.Equal(t, flat, mapstostructs.Flatten(nested, "."), "flattening again should give the same result")
+	}
+
+	_, err = mapstostructs.Unflatten(map[string]interface{}{"a__b__c": 1, "a__b": 2}, "__")
+
+	if assert.NotNil(t, err, "error should not be nil with conflicting keys") {
+		assert.Equal(t, "the key 'a__b__c' conflicts with the value for the key 'a__b'", err.Error())
+	}
+}
+
+func TestSeparatorSparseIndex(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Separator: "."}
+
+	var customer Customer
+
+	err := decoder.MapToStruct(map[string]interface{}{"orders.4611686018427387903.id": 1}, &customer)
+
+	if assert.NotNil(t, err, "error should not be nil for a sparse index in a path") {
+		assert.Equal(t, "the Orders field for a struct of type Customer the map keys for a []mapstostructs_test.Order must not leave more than 1000 indexes missing, but index 4611686018427387903 leaves 4611686018427387903", err.Error())
+	}
+}
+
+func TestFlattenEmptySeparator(t *testing.T) {
+	input := map[string]interface{}{"location": map[string]interface{}{"city": "London"}}
+
+	assert.Equal(t, input, mapstostructs.Flatten(input, ""), "an empty separator should not flatten")
+
+	_, err := mapstostructs.Unflatten(map[string]interface{}{"ab": 1}, "")
+
+	if assert.NotNil(t, err, "error should not be nil with an empty separator") {
+		assert.Equal(t, "the separator must not be empty", err.Error())
+	}
+}
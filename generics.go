@@ -0,0 +1,53 @@
+package mapstostructs
+
+// MapToStructOf returns a struct of type T populated from a map as MapToStruct does, so that the receiver type is
+// checked at compile time. T must be a struct type.
+func MapToStructOf[T any](input map[string]interface{}, tags ...string) (T, error) {
+	var receiver T
+	err := MapToStruct(input, &receiver, tags...)
+	return receiver, err
+}
+
+// MapsToStructsOf returns a slice of T populated from a slice of maps as MapsToStructs does. T must be a struct type
+// or a pointer to one.
+func MapsToStructsOf[T any](input []map[string]interface{}, tags ...string) ([]T, error) {
+	var receiver []T
+	err := MapsToStructs(input, &receiver, tags...)
+	return receiver, err
+}
+
+// TuplesToStructsOf returns a slice of T populated from a slice of tuples as TuplesToStructs does. T must be a struct
+// type or a pointer to one.
+func TuplesToStructsOf[T any](input [][]interface{}, tags ...string) ([]T, error) {
+	var receiver []T
+	err := TuplesToStructs(input, &receiver, tags...)
+	return receiver, err
+}
+
+// MapToMapOf returns a map[K]V populated from a map as MapToMap does.
+func MapToMapOf[K comparable, V any](input interface{}, tags ...string) (map[K]V, error) {
+	var receiver map[K]V
+	err := MapToMap(input, &receiver, tags...)
+	return receiver, err
+}
+
+// SliceToSliceOf returns a slice of T populated from a slice or an array as SliceToSlice does.
+func SliceToSliceOf[T any](input interface{}, tags ...string) ([]T, error) {
+	var receiver []T
+	err := SliceToSlice(input, &receiver, tags...)
+	return receiver, err
+}
+
+// MapsToKeyedMapOf returns a map[K]V populated from a slice of rows as MapsToKeyedMap does.
+func MapsToKeyedMapOf[K comparable, V any](input interface{}, tags ...string) (map[K]V, error) {
+	var receiver map[K]V
+	err := MapsToKeyedMap(input, &receiver, tags...)
+	return receiver, err
+}
+
+// ColumnsToStructsOf returns a slice of T populated from a map of columns as ColumnsToStructs does.
+func ColumnsToStructsOf[T any](input interface{}, tags ...string) ([]T, error) {
+	var receiver []T
+	err := ColumnsToStructs(input, &receiver, tags...)
+	return receiver, err
+}
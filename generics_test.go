@@ -0,0 +1,70 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+func TestMapToStructOf(t *testing.T) {
+	user, err := mapstostructs.MapToStructOf[UserWithTags](map[string]interface{}{"id": 213, "sex": "male"}, "alias")
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, UserWithTags{ID: 213, Gender: "male"}, user, "values should be correctly set")
+	}
+
+	_, err = mapstostructs.MapToStructOf[string](map[string]interface{}{"id": 213})
+
+	if assert.NotNil(t, err, "error should not be nil for a non-struct type") {
+		assert.Equal(t, "the receiver argument must be a ptr to a struct but a ptr to a string was given", err.Error())
+	}
+}
+
+func TestMapsToStructsOf(t *testing.T) {
+	maps := []map[string]any{
+		{"id": 213, "name": "Zhaoliu"},
+		{"id": 56, "name": "Zhangsan"},
+	}
+
+	users, err := mapstostructs.MapsToStructsOf[*User](maps)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		if assert.Equal(t, 2, len(users), "all rows should be returned") {
+			assert.Equal(t, "Zhangsan", users[1].Name, "values should be correctly set")
+		}
+	}
+
+	_, err = mapstostructs.MapsToStructsOf[User]([]map[string]any{{"id": "invalid"}})
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the ID field for a struct of type User must be or be convertible to int type, but received 'invalid' in row 1", err.Error())
+	}
+}
+
+func TestMapToMapOf(t *testing.T) {
+	receiver, err := mapstostructs.MapToMapOf[int, Location](map[string]interface{}{
+		"5": map[string]interface{}{"city": "London"},
+	})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, "London", receiver[5].City, "values should be correctly set")
+	}
+}
+
+func TestSliceToSliceOf(t *testing.T) {
+	receiver, err := mapstostructs.SliceToSliceOf[[]string]([]interface{}{[]interface{}{"a"}})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, [][]string{{"a"}}, receiver, "values should be correctly set")
+	}
+}
+
+func TestTuplesToStructsOf(t *testing.T) {
+	receiver, err := mapstostructs.TuplesToStructsOf[Reply]([][]interface{}{{1, "a"}})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []Reply{{ID: 1, Name: "a"}}, receiver, "values should be correctly set")
+	}
+}
@@ -0,0 +1,47 @@
+package mapstostructs
+
+import (
+	"reflect"
+	"strings"
+)
+
+const groupsTag = "groups"
+
+// inGroups reports whether a struct field is in any of the active groups given by the Groups setting. Fields without
+// a groups tag are in every group, and all fields are active if the Groups setting is empty.
+func (d *Decoder) inGroups(field reflect.StructField) bool {
+	if len(d.Groups) == 0 {
+		return true
+	}
+	tag, ok := field.Tag.Lookup(groupsTag)
+	if !ok {
+		return true
+	}
+	for _, group := range strings.Split(tag, ",") {
+		for _, active := range d.Groups {
+			if strings.TrimSpace(group) == active {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inactiveFields returns the names of the exported fields of a struct type outside the active groups, or nil if
+// there are none.
+func (d *Decoder) inactiveFields(structType reflect.Type) map[string]bool {
+	if len(d.Groups) == 0 {
+		return nil
+	}
+	var inactive map[string]bool
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath == "" && !d.inGroups(field) {
+			if inactive == nil {
+				inactive = make(map[string]bool)
+			}
+			inactive[field.Name] = true
+		}
+	}
+	return inactive
+}
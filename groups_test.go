@@ -0,0 +1,82 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Employee struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Salary int    `json:"salary" groups:"admin" validate:"min=1" default:"100"`
+	Notes  string `json:"notes" groups:"admin, internal"`
+}
+
+func TestGroupsUnset(t *testing.T) {
+	var employee Employee
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"id": 1, "name": "Zhaoliu", "salary": 50000, "notes": "n"}, &employee)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Employee{ID: 1, Name: "Zhaoliu", Salary: 50000, Notes: "n"}, employee, "all fields should be active without groups")
+	}
+}
+
+func TestGroups(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Groups: []string{"internal"}, ErrorUnmapped: true}
+
+	var employee Employee
+
+	err := decoder.MapToStruct(map[string]interface{}{"id": 1, "name": "Zhaoliu", "salary": 50000, "notes": "n"}, &employee)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Employee{ID: 1, Name: "Zhaoliu", Notes: "n"}, employee, "fields outside the active groups should be ignored")
+	}
+}
+
+func TestGroupsStructToMap(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Groups: []string{"internal"}}
+
+	output, err := decoder.StructToMap(Employee{ID: 1, Name: "Zhaoliu", Salary: 50000, Notes: "n"})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{"id": 1, "name": "Zhaoliu", "notes": "n"}, output, "fields outside the active groups should be omitted")
+	}
+}
+
+func TestGroupsStructsToColumns(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Groups: []string{"public"}}
+
+	columns, err := decoder.StructsToColumns([]Employee{{ID: 1}})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{"id": []interface{}{1}, "name": []interface{}{""}}, columns, "fields outside the active groups should have no columns")
+	}
+}
+
+func TestGroupsDefaults(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Groups: []string{"admin"}}
+
+	var employee Employee
+
+	err := decoder.MapToStruct(map[string]interface{}{"id": 1}, &employee)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Employee{ID: 1, Salary: 100}, employee, "defaults should be set for active fields")
+	}
+}
+
+func TestGroupsValidation(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Groups: []string{"admin"}}
+
+	var employee Employee
+
+	err := decoder.MapToStruct(map[string]interface{}{"salary": 0}, &employee)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the Salary field for a struct of type Employee must be at least 1, but received '0'", err.Error())
+	}
+}
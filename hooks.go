@@ -0,0 +1,59 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const hookPrefix = "the %s hook for a struct of type %s failed: "
+
+// BeforeMapDecoder is implemented by struct types which rewrite their input before being populated from a map, for
+// example to rename or derive keys. The input passed is a copy which may be modified and returned.
+type BeforeMapDecoder interface {
+	BeforeMapDecode(input map[string]interface{}) (map[string]interface{}, error)
+}
+
+// AfterMapDecoder is implemented by struct types which normalise or check themselves after being populated, for
+// example checking that an end time is after a start time. Nested structs are populated, and their hooks called,
+// before the structs containing them.
+type AfterMapDecoder interface {
+	AfterMapDecode() error
+}
+
+var (
+	beforeMapDecoderType = reflect.TypeOf((*BeforeMapDecoder)(nil)).Elem()
+	afterMapDecoderType  = reflect.TypeOf((*AfterMapDecoder)(nil)).Elem()
+)
+
+// beforeMapDecode calls the BeforeMapDecode hook of a struct with a copy of its input as a map[string]interface{}.
+func beforeMapDecode(structValue reflect.Value, input reflect.Value) (reflect.Value, error) {
+	structType := structValue.Type()
+	copied := make(map[string]interface{}, input.Len())
+	mapRange := input.MapRange()
+	for mapRange.Next() {
+		key := mapRange.Key()
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		if key.Kind() != reflect.String {
+			return reflect.Value{}, fmt.Errorf(badStructKeyMsg, structType.Name(), describeType(key), describeValue(key))
+		}
+		copied[key.String()] = mapRange.Value().Interface()
+	}
+	output, err := structValue.Addr().Interface().(BeforeMapDecoder).BeforeMapDecode(copied)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf(hookPrefix+"%s", "BeforeMapDecode", structType.Name(), err)
+	}
+	if output == nil {
+		output = map[string]interface{}{}
+	}
+	return reflect.ValueOf(output), nil
+}
+
+// afterMapDecode calls the AfterMapDecode hook of a populated struct whose pointer type implements AfterMapDecoder.
+func afterMapDecode(structValue reflect.Value) error {
+	if err := structValue.Addr().Interface().(AfterMapDecoder).AfterMapDecode(); err != nil {
+		return fmt.Errorf(hookPrefix+"%s", "AfterMapDecode", structValue.Type().Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package mapstostructs_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Period struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+	Calls []string
+}
+
+func (p *Period) BeforeMapDecode(input map[string]interface{}) (map[string]interface{}, error) {
+	if from, ok := input["from"]; ok {
+		delete(input, "from")
+		input["start"] = from
+	}
+	if _, ok := input["error"]; ok {
+		return nil, fmt.Errorf("%v", input["error"])
+	}
+	return input, nil
+}
+
+func (p *Period) AfterMapDecode() error {
+	if p.End < p.Start {
+		return fmt.Errorf("the end %d is before the start %d", p.End, p.Start)
+	}
+	return nil
+}
+
+type Booking struct {
+	Name    string    `json:"name"`
+	Period  Period    `json:"period"`
+	Periods []*Period `json:"periods"`
+	Hooked  string
+}
+
+func (b *Booking) AfterMapDecode() error {
+	b.Name = strings.TrimSpace(b.Name)
+	b.Hooked = fmt.Sprintf("%d-%d", b.Period.Start, b.Period.End)
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	input := map[string]interface{}{
+		"name":   " Zhaoliu ",
+		"period": map[string]interface{}{"from": 1, "end": 2},
+	}
+
+	var booking Booking
+
+	err := mapstostructs.MapToStruct(input, &booking)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Booking{Name: "Zhaoliu", Period: Period{Start: 1, End: 2}, Hooked: "1-2"}, booking, "hooks should be called deepest first")
+	}
+	assert.Equal(t, map[string]interface{}{"from": 1, "end": 2}, input["period"], "the input should not be modified")
+}
+
+func TestHooksEmptyInput(t *testing.T) {
+	var period Period
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{}, &period)
+
+	assert.Nil(t, err, "error should be nil for valid call")
+}
+
+func TestAfterMapDecodeError(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "a"},
+		{"name": "b", "periods": []interface{}{map[string]interface{}{"start": 3, "end": 4}, map[string]interface{}{"start": 2, "end": 1}}},
+	}
+
+	var bookings []Booking
+
+	err := mapstostructs.MapsToStructs(rows, &bookings)
+
+	if assert.NotNil(t, err, "error should not be nil with a failing hook") {
+		assert.Equal(t, "the Periods field for a struct of type Booking the AfterMapDecode hook for a struct of type Period failed: the end 1 is before the start 2 in row 2 in row 2", err.Error())
+	}
+}
+
+func TestBeforeMapDecodeError(t *testing.T) {
+	var booking Booking
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"period": map[string]interface{}{"error": "no periods"}}, &booking)
+
+	if assert.NotNil(t, err, "error should not be nil with a failing hook") {
+		assert.Equal(t, "the Period field for a struct of type Booking the BeforeMapDecode hook for a struct of type Period failed: no periods", err.Error())
+	}
+}
+
+func TestHooksTuples(t *testing.T) {
+	err := mapstostructs.SliceToSlice([][]int{{5, 4}}, &[]Period{})
+
+	if assert.NotNil(t, err, "error should not be nil with a failing hook") {
+		assert.Equal(t, "the AfterMapDecode hook for a struct of type Period failed: the end 4 is before the start 5 in row 1", err.Error())
+	}
+}
+
+type Discount struct {
+	Percent int `json:"percent"`
+}
+
+func (d *Discount) AfterMapDecode() error {
+	if d.Percent > 100 {
+		return fmt.Errorf("discount must be <= 100%%, but received %d%%", d.Percent)
+	}
+	return nil
+}
+
+func TestHookErrorWithPercent(t *testing.T) {
+	var discounts []Discount
+
+	err := mapstostructs.MapsToStructs([]map[string]interface{}{{"percent": 150}}, &discounts)
+
+	if assert.NotNil(t, err, "error should not be nil with a failing hook") {
+		assert.Equal(t, "the AfterMapDecode hook for a struct of type Discount failed: discount must be <= 100%, but received 150% in row 1", err.Error())
+	}
+}
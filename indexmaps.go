@@ -0,0 +1,135 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+const (
+	badIndexMsg    = "must be a non-negative integer index, but received '%v'"
+	indexGapMsg    = "the map keys for a %s must be contiguous indexes from 0, but index %d is missing"
+	indexRangeMsg  = "must be an index less than %d, but received '%v'"
+	indexRepeatMsg = "the map keys for a %s must be distinct indexes, but index %d is repeated"
+	indexSpanMsg   = "the map keys for a %s must not leave more than %d indexes missing, but index %d leaves %d"
+
+	// defaultMaxIndexGaps is the number of missing indexes allowed when MaxIndexGaps is zero.
+	defaultMaxIndexGaps = 1000
+)
+
+// IndexGapPolicy sets how gaps in the indexes of a map populating a slice or an array are handled.
+type IndexGapPolicy int
+
+const (
+	// IndexGapsZero leaves the elements at missing indexes as zero values.
+	IndexGapsZero IndexGapPolicy = iota
+	// IndexGapsCompact drops missing indexes, so that the elements are consecutive in index order.
+	IndexGapsCompact
+	// IndexGapsError returns an error for a missing index.
+	IndexGapsError
+)
+
+var intType = reflect.TypeOf(0)
+
+// setSliceFromIndexMap populates a slice or an array from a map keyed by integers or strings of integers, as sent by
+// PHP backends and some form encoders.
+func (d *Decoder) setSliceFromIndexMap(receiver reflect.Value, input reflect.Value) error {
+	if input.Len() == 0 {
+		return nil
+	}
+	wantType := receiver.Type()
+	if receiver.Kind() == reflect.Ptr {
+		wantType = wantType.Elem()
+	}
+	values := make(map[int]reflect.Value, input.Len())
+	indexes := make([]int, 0, input.Len())
+	mapRange := input.MapRange()
+	for mapRange.Next() {
+		inputKey := mapRange.Key()
+		if inputKey.Kind() == reflect.Interface {
+			inputKey = inputKey.Elem()
+		}
+		key, ok := convertToType(inputKey, intType, true)
+		if !ok || key.Int() < 0 {
+			return fmt.Errorf(mapKeyPrefix+badIndexMsg, wantType.String(), describeValue(inputKey))
+		}
+		index := int(key.Int())
+		if _, ok := values[index]; ok {
+			return fmt.Errorf(indexRepeatMsg, wantType.String(), index)
+		}
+		values[index] = mapRange.Value()
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	last := indexes[len(indexes)-1]
+	length := len(indexes)
+	switch d.IndexGaps {
+	case IndexGapsZero:
+		if wantType.Kind() == reflect.Array && last >= wantType.Len() {
+			return fmt.Errorf(mapKeyPrefix+indexRangeMsg, wantType.String(), wantType.Len(), last)
+		} else if missing := last - (length - 1); wantType.Kind() == reflect.Slice && missing > d.maxIndexGaps() {
+			return fmt.Errorf(indexSpanMsg, wantType.String(), d.maxIndexGaps(), last, missing)
+		}
+		length = last + 1
+	case IndexGapsError:
+		for i, index := range indexes {
+			if index != i {
+				return fmt.Errorf(indexGapMsg, wantType.String(), i)
+			}
+		}
+	}
+
+	var newValue reflect.Value
+	if wantType.Kind() == reflect.Array {
+		if length > wantType.Len() {
+			return fmt.Errorf(mapKeyPrefix+indexRangeMsg, wantType.String(), wantType.Len(), last)
+		}
+		newValue = reflect.New(wantType).Elem()
+	} else {
+		newValue = reflect.MakeSlice(wantType, length, length)
+	}
+	for i, index := range indexes {
+		position := index
+		if d.IndexGaps == IndexGapsCompact {
+			position = i
+		}
+		if err := d.setRecursively(newValue.Index(position), values[index]); err != nil {
+			return fmt.Errorf("%s"+rowSuffix, err, index+1)
+		}
+	}
+	setValue(receiver, newValue)
+	return nil
+}
+
+// maxIndexGaps returns the number of missing indexes allowed in a map populating a slice, which bounds the length of
+// the slice allocated for it.
+func (d *Decoder) maxIndexGaps() int {
+	if d.MaxIndexGaps == 0 {
+		return defaultMaxIndexGaps
+	}
+	return d.MaxIndexGaps
+}
+
+// indexMap returns a map of the elements of a slice or an array keyed by their indexes.
+func indexMap(input reflect.Value) reflect.Value {
+	output := reflect.MakeMapWithSize(reflect.MapOf(intType, input.Type().Elem()), input.Len())
+	for i := 0; i < input.Len(); i++ {
+		output.SetMapIndex(reflect.ValueOf(i), input.Index(i))
+	}
+	return output
+}
+
+// indexMapInterface is as indexMap for the output of toInterface.
+func (d *Decoder) indexMapInterface(input reflect.Value) (map[string]interface{}, error) {
+	output := make(map[string]interface{}, input.Len())
+	for i := 0; i < input.Len(); i++ {
+		value, err := d.toInterface(input.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("%s"+rowSuffix, err, i+1)
+		}
+		output[strconv.Itoa(i)] = value
+	}
+	return output, nil
+}
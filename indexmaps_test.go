@@ -0,0 +1,168 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Form struct {
+	Tags   []string   `json:"tags"`
+	Pair   [2]int     `json:"pair"`
+	Places []Location `json:"places"`
+}
+
+func TestIndexMapsToSlices(t *testing.T) {
+	decoder := &mapstostructs.Decoder{IndexMapsToSlices: true}
+	input := map[string]interface{}{
+		"tags":   map[string]interface{}{"1": "b", "0": "a", "3": "d"},
+		"pair":   map[string]interface{}{"1": 16},
+		"places": map[interface{}]interface{}{"0": map[string]interface{}{"city": "London"}},
+	}
+
+	var form Form
+
+	err := decoder.MapToStruct(input, &form)
+
+	if assert.Nil(t, err, "error should be nil for index maps") {
+		assert.Equal(t, []string{"a", "b", "", "d"}, form.Tags, "gaps should be left as zero values by default")
+		assert.Equal(t, [2]int{0, 16}, form.Pair, "arrays should be populated")
+		assert.Equal(t, []Location{{City: "London"}}, form.Places, "elements should be converted")
+	}
+
+	decoder.IndexGaps = mapstostructs.IndexGapsCompact
+
+	err = decoder.MapToStruct(input, &form)
+
+	if assert.Nil(t, err, "error should be nil for index maps") {
+		assert.Equal(t, []string{"a", "b", "d"}, form.Tags, "gaps should be dropped when compacting")
+	}
+
+	decoder.IndexGaps = mapstostructs.IndexGapsError
+
+	err = decoder.MapToStruct(map[string]interface{}{"tags": input["tags"]}, &form)
+
+	if assert.NotNil(t, err, "error should not be nil for gaps") {
+		assert.Equal(t, "the Tags field for a struct of type Form the map keys for a []string must be contiguous indexes from 0, but index 2 is missing", err.Error())
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"pair": map[int]int{0: 1, 2: 3}}, &form)
+
+	if assert.NotNil(t, err, "error should not be nil for indexes beyond an array") {
+		assert.Equal(t, "the Pair field for a struct of type Form the map keys for a [2]int must be contiguous indexes from 0, but index 1 is missing", err.Error())
+	}
+
+	decoder.IndexGaps = mapstostructs.IndexGapsZero
+
+	err = decoder.MapToStruct(map[string]interface{}{"pair": map[int]int{0: 1, 2: 3}}, &form)
+
+	if assert.NotNil(t, err, "error should not be nil for indexes beyond an array") {
+		assert.Equal(t, "the Pair field for a struct of type Form the map key for a [2]int must be an index less than 2, but received '2'", err.Error())
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"tags": map[string]interface{}{"a": "b"}}, &form)
+
+	if assert.NotNil(t, err, "error should not be nil for non-index keys") {
+		assert.Equal(t, "the Tags field for a struct of type Form the map key for a []string must be a non-negative integer index, but received 'a'", err.Error())
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"tags": map[string]interface{}{"0": 1}}, &form)
+
+	if assert.NotNil(t, err, "error should not be nil for bad elements") {
+		assert.Equal(t, "the Tags field for a struct of type Form must be or be convertible to string type, but received '1' in row 1", err.Error())
+	}
+
+	err = mapstostructs.MapToStruct(input, &form)
+
+	assert.NotNil(t, err, "index maps should not populate slices by default")
+}
+
+func TestIndexMapsToSlicesSparse(t *testing.T) {
+	decoder := &mapstostructs.Decoder{IndexMapsToSlices: true}
+
+	var form Form
+
+	err := decoder.MapToStruct(map[string]interface{}{"tags": map[string]interface{}{"9223372036854775806": "a"}}, &form)
+
+	if assert.NotNil(t, err, "error should not be nil for a sparse index") {
+		assert.Equal(t, "the Tags field for a struct of type Form the map keys for a []string must not leave more than 1000 indexes missing, but index 9223372036854775806 leaves 9223372036854775806", err.Error())
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"pair": map[string]interface{}{"9223372036854775807": 1}}, &form)
+
+	if assert.NotNil(t, err, "error should not be nil for a sparse index beyond an array") {
+		assert.Equal(t, "the Pair field for a struct of type Form the map key for a [2]int must be an index less than 2, but received '9223372036854775807'", err.Error())
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"tags": map[string]interface{}{"0": "a", "1001": "b"}}, &form)
+
+	if assert.Nil(t, err, "error should be nil for up to 1000 missing indexes") {
+		assert.Len(t, form.Tags, 1002, "missing indexes should be zero values")
+	}
+
+	decoder.MaxIndexGaps = 10
+
+	err = decoder.MapToStruct(map[string]interface{}{"tags": map[string]interface{}{"0": "a", "12": "b"}}, &form)
+
+	if assert.NotNil(t, err, "error should not be nil beyond the limit set") {
+		assert.Equal(t, "the Tags field for a struct of type Form the map keys for a []string must not leave more than 10 indexes missing, but index 12 leaves 11", err.Error())
+	}
+
+	decoder.IndexGaps = mapstostructs.IndexGapsCompact
+
+	err = decoder.MapToStruct(map[string]interface{}{"tags": map[string]interface{}{"0": "a", "9223372036854775807": "b"}}, &form)
+
+	if assert.Nil(t, err, "error should be nil for a sparse index when compacting") {
+		assert.Equal(t, []string{"a", "b"}, form.Tags, "sparse indexes should be compacted")
+	}
+}
+
+func TestIndexMapsToSlicesRepeated(t *testing.T) {
+	decoder := &mapstostructs.Decoder{IndexMapsToSlices: true}
+
+	var form Form
+
+	err := decoder.MapToStruct(map[string]interface{}{"tags": map[string]interface{}{"1": "a", "01": "b"}}, &form)
+
+	if assert.NotNil(t, err, "error should not be nil for a repeated index") {
+		assert.Equal(t, "the Tags field for a struct of type Form the map keys for a []string must be distinct indexes, but index 1 is repeated", err.Error())
+	}
+}
+
+func TestSlicesToIndexMaps(t *testing.T) {
+	decoder := &mapstostructs.Decoder{SlicesToIndexMaps: true}
+
+	out, err := decoder.StructToMap(Form{Tags: []string{"a", "b"}, Pair: [2]int{1, 2}})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{"0": "a", "1": "b"}, out["tags"], "slices should be output as index maps")
+		assert.Equal(t, map[string]interface{}{"0": 1, "1": 2}, out["pair"], "arrays should be output as index maps")
+		assert.Nil(t, out["places"], "nil slices should be output as nil")
+	}
+
+	var byIndex map[int]string
+
+	err = decoder.Convert([]interface{}{"a", "b"}, &byIndex)
+
+	if assert.Nil(t, err, "error should be nil for a slice into an int-keyed map") {
+		assert.Equal(t, map[int]string{0: "a", 1: "b"}, byIndex)
+	}
+
+	var byString map[string]int
+
+	err = decoder.Convert([]float64{7}, &byString)
+
+	if assert.Nil(t, err, "error should be nil for a slice into a string-keyed map") {
+		assert.Equal(t, map[string]int{"0": 7}, byString)
+	}
+
+	var form Form
+
+	err = (&mapstostructs.Decoder{IndexMapsToSlices: true}).MapToStruct(out, &form)
+
+	if assert.Nil(t, err, "the output should convert back") {
+		assert.Equal(t, Form{Tags: []string{"a", "b"}, Pair: [2]int{1, 2}}, form, "the round trip should preserve values")
+	}
+}
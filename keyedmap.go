@@ -0,0 +1,133 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	keyTag          = "key"
+	noKeyFieldMsg   = "the key field for a %s must be given by the KeyField setting or a key tag"
+	missingKeyMsg   = "the key field '%s' for a %s is missing"
+	duplicateKeyMsg = "the key '%v' for a %s is duplicated"
+)
+
+// DuplicateKeyPolicy sets how rows with the same key are handled by MapsToKeyedMap when the map values are not slices.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysError returns an error for a row with the same key as an earlier row.
+	DuplicateKeysError DuplicateKeyPolicy = iota
+	// DuplicateKeysLastWins keeps the last of the rows with the same key.
+	DuplicateKeysLastWins
+)
+
+// setKeyedMap populates a map from a slice of rows, keying each row by the value of its key field. Rows are grouped
+// when the map values are slices.
+func (d *Decoder) setKeyedMap(receiver reflect.Value, input reflect.Value) error {
+	mapType := receiver.Type()
+	if receiver.Kind() == reflect.Ptr {
+		mapType = mapType.Elem()
+	}
+	rowType := mapType.Elem()
+	grouped := rowType.Kind() == reflect.Slice
+	if grouped {
+		rowType = rowType.Elem()
+	}
+	keyName, err := d.keyName(rowType, mapType)
+	if err != nil {
+		return err
+	}
+	newMapValue := reflect.MakeMapWithSize(mapType, input.Len())
+	for i := 0; i < input.Len(); i++ {
+		row := input.Index(i)
+		for row.Kind() == reflect.Ptr || row.Kind() == reflect.Interface {
+			row = row.Elem()
+		}
+		if !row.IsValid() {
+			continue
+		}
+		inputKey, ok := d.rowKey(row, keyName)
+		if !ok {
+			return fmt.Errorf(missingKeyMsg+rowSuffix, keyName, mapType.String(), i+1)
+		}
+		key, handled, err := convertEnum(inputKey, mapType.Key())
+		if err != nil {
+			return fmt.Errorf(mapKeyPrefix+"%s"+rowSuffix, mapType.String(), err, i+1)
+		}
+		if !handled {
+			key, ok = d.convert(inputKey, mapType.Key(), true)
+		}
+		if !ok {
+			return fmt.Errorf(mapKeyPrefix+"%s"+rowSuffix, mapType.String(), d.badValue(inputKey, mapType.Key()), i+1)
+		}
+
+		newElement := reflect.New(rowType).Elem()
+		if err := d.setRecursively(newElement, row); err != nil {
+			return fmt.Errorf("%s"+rowSuffix, err, i+1)
+		}
+		existing := newMapValue.MapIndex(key)
+		switch {
+		case grouped && existing.IsValid():
+			newElement = reflect.Append(existing, newElement)
+		case grouped:
+			newElement = reflect.Append(reflect.MakeSlice(mapType.Elem(), 0, 1), newElement)
+		case existing.IsValid() && d.DuplicateKeys == DuplicateKeysError:
+			return fmt.Errorf(duplicateKeyMsg+rowSuffix, key.Interface(), mapType.String(), i+1)
+		}
+		newMapValue.SetMapIndex(key, newElement)
+	}
+	setValue(receiver, newMapValue)
+	return nil
+}
+
+// keyName returns the KeyField setting, or else the map key of the struct field with a key tag.
+func (d *Decoder) keyName(rowType reflect.Type, mapType reflect.Type) (string, error) {
+	if d.KeyField != "" {
+		return d.KeyField, nil
+	}
+	for rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+	if rowType.Kind() == reflect.Struct {
+		for i := 0; i < rowType.NumField(); i++ {
+			field := rowType.Field(i)
+			if _, ok := field.Tag.Lookup(keyTag); ok {
+				return fieldKey(field, d.Tags), nil
+			}
+		}
+	}
+	return "", fmt.Errorf(noKeyFieldMsg, mapType.String())
+}
+
+// rowKey returns the value for the key name in a row which is a map or a struct, matching the key name as map keys
+// are matched to struct fields.
+func (d *Decoder) rowKey(row reflect.Value, keyName string) (reflect.Value, bool) {
+	var value reflect.Value
+	switch row.Kind() {
+
+	case reflect.Map:
+		mapRange := row.MapRange()
+		for mapRange.Next() {
+			key := mapRange.Key()
+			if key.Kind() == reflect.Interface {
+				key = key.Elem()
+			}
+			if key.Kind() == reflect.String && strings.EqualFold(key.String(), keyName) {
+				value = mapRange.Value()
+				break
+			}
+		}
+
+	case reflect.Struct:
+		if fieldName, ok := makeTagMap(row.Type(), d.Tags)[strings.ToLower(keyName)]; ok {
+			value = row.FieldByName(fieldName)
+		}
+	}
+
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	return value, value.IsValid()
+}
@@ -0,0 +1,118 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type KeyedUser struct {
+	ID     int    `json:"id" key:"true"`
+	Name   string `json:"name"`
+	Gender string `json:"gender"`
+}
+
+func TestMapsToKeyedMap(t *testing.T) {
+	maps := []map[string]interface{}{
+		{"id": 213, "name": "Zhaoliu", "gender": "male"},
+		{"id": float64(56), "name": "Zhangsan", "gender": "male"},
+		{"id": 7, "name": "Lisi", "gender": "female"},
+	}
+
+	var users map[int]KeyedUser
+
+	err := mapstostructs.MapsToKeyedMap(maps, &users)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		if assert.Equal(t, 3, len(users), "all rows should be returned") {
+			assert.Equal(t, "Zhaoliu", users[213].Name, "rows should be keyed by the key tag field")
+			assert.Equal(t, "Zhangsan", users[56].Name, "key values should be converted")
+			assert.Equal(t, "Lisi", users[7].Name, "rows should be keyed by the key tag field")
+		}
+	}
+
+	byName, err := mapstostructs.MapsToKeyedMapOf[string, *User](maps)
+
+	if assert.NotNil(t, err, "error should not be nil without a key field") {
+		assert.Equal(t, "the key field for a map[string]*mapstostructs_test.User must be given by the KeyField setting or a key tag", err.Error())
+	}
+
+	decoder := &mapstostructs.Decoder{KeyField: "NAME"}
+
+	err = decoder.MapsToKeyedMap(maps, &byName)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, 56, byName["Zhangsan"].ID, "rows should be keyed by the KeyField setting")
+	}
+
+	decoder.KeyField = "gender"
+
+	err = decoder.MapsToKeyedMap(maps, &byName)
+
+	if assert.NotNil(t, err, "error should not be nil with duplicate keys") {
+		assert.Equal(t, "the key 'male' for a map[string]*mapstostructs_test.User is duplicated in row 2", err.Error())
+	}
+
+	decoder.DuplicateKeys = mapstostructs.DuplicateKeysLastWins
+
+	err = decoder.MapsToKeyedMap(maps, &byName)
+
+	if assert.Nil(t, err, "error should be nil with the last-wins policy") {
+		assert.Equal(t, 56, byName["male"].ID, "the last duplicate should win")
+	}
+
+	var grouped map[string][]KeyedUser
+
+	err = decoder.MapsToKeyedMap(maps, &grouped)
+
+	if assert.Nil(t, err, "error should be nil for grouping") {
+		if assert.Equal(t, 2, len(grouped["male"]), "rows should be grouped") {
+			assert.Equal(t, 213, grouped["male"][0].ID, "groups should be in input order")
+			assert.Equal(t, 56, grouped["male"][1].ID, "groups should be in input order")
+		}
+		assert.Equal(t, 1, len(grouped["female"]), "rows should be grouped")
+	}
+}
+
+func TestMapsToKeyedMapStructs(t *testing.T) {
+	users := []*User{{ID: 1, Name: "a"}, nil, {ID: 2, Name: "b"}}
+
+	var byID map[string]map[string]interface{}
+
+	err := (&mapstostructs.Decoder{KeyField: "id"}).MapsToKeyedMap(users, &byID)
+
+	if assert.Nil(t, err, "error should be nil for struct rows") {
+		assert.Equal(t, "b", byID["2"]["name"], "struct rows should be keyed and converted")
+		assert.Equal(t, 2, len(byID), "nil rows should be skipped")
+	}
+}
+
+func TestMapsToKeyedMapErrors(t *testing.T) {
+	var users map[int]KeyedUser
+
+	err := mapstostructs.MapsToKeyedMap([]map[string]interface{}{{"id": 1}, {"name": "x"}}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with a missing key") {
+		assert.Equal(t, "the key field 'id' for a map[int]mapstostructs_test.KeyedUser is missing in row 2", err.Error())
+	}
+
+	err = mapstostructs.MapsToKeyedMap([]map[string]interface{}{{"id": "x"}}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with a bad key") {
+		assert.Equal(t, "the map key for a map[int]mapstostructs_test.KeyedUser must be or be convertible to int type, but received 'x' in row 1", err.Error())
+	}
+
+	err = mapstostructs.MapsToKeyedMap([]map[string]interface{}{{"id": 1, "name": 2}}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with a bad value") {
+		assert.Equal(t, "the Name field for a struct of type KeyedUser must be or be convertible to string type, but received '2' in row 1", err.Error())
+	}
+
+	err = mapstostructs.MapsToKeyedMap(map[string]interface{}{}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid input") {
+		assert.Equal(t, "the input argument must be a slice or an array but a map was given", err.Error())
+	}
+}
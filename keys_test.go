@@ -0,0 +1,85 @@
+package mapstostructs_test
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+func TestMapToMapKeys(t *testing.T) {
+	var flags map[bool]string
+
+	err := mapstostructs.MapToMap(map[string]interface{}{"true": "yes", "false": "no"}, &flags)
+
+	if assert.Nil(t, err, "error should be nil for bool keys") {
+		assert.Equal(t, map[bool]string{true: "yes", false: "no"}, flags)
+	}
+
+	var times map[time.Time]int
+
+	err = mapstostructs.MapToMap(map[string]interface{}{"2022-05-01T10:00:00Z": 1}, &times)
+
+	if assert.Nil(t, err, "error should be nil for time keys") {
+		assert.Equal(t, 1, times[time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)])
+	}
+
+	var addrs map[netip.Addr]string
+
+	err = mapstostructs.MapToMap(map[string]interface{}{"10.0.0.1": "gateway"}, &addrs)
+
+	if assert.Nil(t, err, "error should be nil for encoding.TextUnmarshaler keys") {
+		assert.Equal(t, "gateway", addrs[netip.MustParseAddr("10.0.0.1")])
+	}
+
+	err = mapstostructs.MapToMap(map[string]interface{}{"invalid": "gateway"}, &addrs)
+
+	if assert.NotNil(t, err, "error should not be nil for unparsable keys") {
+		assert.Equal(t, "the map key for a map[netip.Addr]string must be or be convertible to netip.Addr type, but received 'invalid'", err.Error())
+	}
+
+	var ids map[uint32]string
+
+	err = mapstostructs.MapToMap(map[string]interface{}{"0xff": "a", "16": "b"}, &ids)
+
+	if assert.Nil(t, err, "error should be nil for hex keys") {
+		assert.Equal(t, map[uint32]string{255: "a", 16: "b"}, ids)
+	}
+
+	var strings map[string]string
+
+	err = mapstostructs.MapToMap(map[netip.Addr]string{netip.MustParseAddr("10.0.0.1"): "gateway"}, &strings)
+
+	if assert.Nil(t, err, "error should be nil for encoding.TextMarshaler keys into string keys") {
+		assert.Equal(t, map[string]string{"10.0.0.1": "gateway"}, strings)
+	}
+
+	err = mapstostructs.MapToMap(map[float64]string{1.5: "a"}, &strings)
+
+	if assert.Nil(t, err, "error should be nil for numeric keys into string keys") {
+		assert.Equal(t, map[string]string{"1.5": "a"}, strings)
+	}
+}
+
+func TestStructToMapKeys(t *testing.T) {
+	in := struct {
+		Flags map[bool]int       `json:"flags"`
+		Times map[time.Time]int  `json:"times"`
+		Addrs map[netip.Addr]int `json:"addrs"`
+	}{
+		Flags: map[bool]int{true: 1},
+		Times: map[time.Time]int{time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC): 2},
+		Addrs: map[netip.Addr]int{netip.MustParseAddr("::1"): 3},
+	}
+
+	out, err := mapstostructs.StructToMap(in)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{"true": 1}, out["flags"], "bool keys should be formatted")
+		assert.Equal(t, map[string]interface{}{"2022-05-01T10:00:00Z": 2}, out["times"], "time keys should be formatted")
+		assert.Equal(t, map[string]interface{}{"::1": 3}, out["addrs"], "encoding.TextMarshaler keys should be formatted")
+	}
+}
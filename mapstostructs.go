@@ -1,22 +1,25 @@
 package mapstostructs
 
-import (
-	"fmt"
-	"reflect"
-)
+import "reflect"
 
 const (
 	notStructSliceReceiverMsg = "the receiver argument must be a ptr to a slice of struct but a %s was given"
 	notStructReceiverMsg      = "the receiver argument must be a ptr to a struct but a %s was given"
 	notMapReceiverMsg         = "the receiver argument must be a ptr to a map but a %s was given"
 	notMapInputMsg            = "the input argument must be a map but a %s was given"
+	notSliceReceiverMsg       = "the receiver argument must be a ptr to a slice but a %s was given"
+	notSliceInputMsg          = "the input argument must be a slice or an array but a %s was given"
+	notStructInputMsg         = "the input argument must be a struct or a ptr to a struct but a %s was given"
+	notStructSliceInputMsg    = "the input argument must be a slice or an array of struct but a %s was given"
+	notPtrReceiverMsg         = "the receiver argument must be a non-nil ptr but a %s was given"
 )
 
 // MapsToStructs provides functionality for a slice of structs to be populated from a slice of map[string]interface{}
 // with the option of passing alternative struct tags to use as map keys. If no tags are specified the json tag is used
 // and if that is not present, the struct field is assumed. Keys are not case-sensitive.
 //
-// The receiver argument must be a pointer to a slice of structs.
+// The receiver argument must be a pointer to a slice of structs, or of pointers to structs at any level of
+// indirection.
 //
 // Type conversions to the struct type are performed where permitted by the reflect library. This helps with the
 // situation where integer values have been JSON-unmarshalled into float64 values in a map.
@@ -25,19 +28,7 @@ const (
 //
 // Maps with numeric keys will accept string representations of numeric values.
 func MapsToStructs(input []map[string]interface{}, receiver interface{}, tags ...string) error {
-	if reflect.ValueOf(receiver).Kind() != reflect.Ptr {
-		return fmt.Errorf(notStructSliceReceiverMsg, reflect.ValueOf(receiver).Kind().String())
-	}
-	structValues := reflect.Indirect(reflect.ValueOf(receiver))
-	if structValues.Kind() != reflect.Slice {
-		return fmt.Errorf(notStructSliceReceiverMsg, "ptr to a "+structValues.Kind().String())
-	}
-	structType := structValues.Type().Elem()
-	if structType.Kind() != reflect.Struct {
-		return fmt.Errorf(notStructSliceReceiverMsg, "ptr to a slice of "+structType.Kind().String())
-	}
-
-	return setSlice(reflect.ValueOf(receiver).Elem(), reflect.ValueOf(input), tags)
+	return NewDecoder(tags...).MapsToStructs(input, receiver)
 }
 
 // MapToStruct provides functionality for a struct to be populated from a map[string]interface{} with the option of
@@ -53,15 +44,65 @@ func MapsToStructs(input []map[string]interface{}, receiver interface{}, tags ..
 //
 // Maps with numeric keys will accept string representations of numeric values.
 func MapToStruct(input map[string]interface{}, receiver interface{}, tags ...string) error {
-	if reflect.ValueOf(receiver).Kind() != reflect.Ptr {
-		return fmt.Errorf(notStructReceiverMsg, reflect.ValueOf(receiver).Kind().String())
-	}
-	structType := reflect.Indirect(reflect.ValueOf(receiver)).Type()
-	if structType.Kind() != reflect.Struct {
-		return fmt.Errorf(notStructReceiverMsg, "ptr to a "+structType.Kind().String())
-	}
+	return NewDecoder(tags...).MapToStruct(input, receiver)
+}
+
+// TuplesToStructs populates a slice of structs from a slice of tuples, such as [[213, "Zhaoliu"], [56, "Zhangsan"]],
+// with the option of passing alternative struct tags to use as map keys. Each tuple populates a struct by position as
+// StructToTuple describes, and is otherwise converted as MapsToStructs converts rows.
+//
+// The receiver argument must be a pointer to a slice of structs, or of pointers to structs at any level of
+// indirection.
+func TuplesToStructs(input [][]interface{}, receiver interface{}, tags ...string) error {
+	return NewDecoder(tags...).TuplesToStructs(input, receiver)
+}
+
+// SliceToSlice allows a slice to be populated from another slice or an array, converting each element as MapToMap
+// converts map values, with the option of passing alternative struct tags to use as map keys. This allows, for
+// example, a []interface{} to populate a []int, a [][]string or a []map[string]T.
+//
+// The receiver argument must be a pointer to a slice.
+//
+// The input argument must be a slice or an array.
+func SliceToSlice(input interface{}, receiver interface{}, tags ...string) error {
+	return NewDecoder(tags...).SliceToSlice(input, receiver)
+}
 
-	return setStructFromMap(reflect.ValueOf(receiver).Elem(), reflect.ValueOf(input), tags)
+// MapsToKeyedMap populates a map from a slice of rows, such as a []map[string]interface{} or a slice of structs,
+// keying each row by the value of one of its fields, with the option of passing alternative struct tags to use as map
+// keys. Each row is converted to the map value type as MapsToStructs converts rows, and the key value to the map key
+// type as MapToMap converts keys.
+//
+// The key field is the struct field of the map value type with a key tag, for example `json:"id" key:"true"`, unless
+// the KeyField setting of a Decoder is used. Rows with the same key are an error, unless the DuplicateKeys setting of
+// a Decoder is used to keep the last of them.
+//
+// If the map value type is a slice, such as map[int][]User, rows with the same key are grouped in input order.
+//
+// The receiver argument must be a pointer to a map.
+//
+// The input argument must be a slice or an array.
+func MapsToKeyedMap(input interface{}, receiver interface{}, tags ...string) error {
+	return NewDecoder(tags...).MapsToKeyedMap(input, receiver)
+}
+
+// ColumnsToStructs populates a slice of structs from column-oriented data, such as {"id": [1, 2], "name": ["a", "b"]},
+// with the option of passing alternative struct tags to use as map keys. Each row is made of the values at the same
+// index in every column, and is converted as MapsToStructs converts rows.
+//
+// The input argument must be a map with string keys, whose values are slices or arrays of equal length.
+//
+// The receiver argument must be a pointer to a slice of structs, or of pointers to structs.
+func ColumnsToStructs(input interface{}, receiver interface{}, tags ...string) error {
+	return NewDecoder(tags...).ColumnsToStructs(input, receiver)
+}
+
+// StructsToColumns provides the reverse of ColumnsToStructs, returning a map of columns, each a []interface{}, keyed
+// and converted as StructToMap keys and converts fields. A nil row has a nil value in every column.
+//
+// The input argument must be a slice or an array of structs, or of pointers to structs.
+func StructsToColumns(input interface{}, tags ...string) (map[string]interface{}, error) {
+	return NewDecoder(tags...).StructsToColumns(input)
 }
 
 // MapToMap allows a map to be populated from another map, allowing key and value conversions where these are
@@ -79,17 +120,108 @@ func MapToStruct(input map[string]interface{}, receiver interface{}, tags ...str
 //
 // Maps with numeric keys will accept string representations of numeric values.
 func MapToMap(input interface{}, receiver interface{}, tags ...string) error {
-	if reflect.ValueOf(receiver).Kind() != reflect.Ptr {
-		return fmt.Errorf(notMapReceiverMsg, reflect.ValueOf(receiver).Kind().String())
-	}
-	mapValue := reflect.Indirect(reflect.ValueOf(receiver))
-	if mapValue.Kind() != reflect.Map {
-		return fmt.Errorf(notMapReceiverMsg, "ptr to a "+mapValue.Kind().String())
-	}
-	inputValue := reflect.ValueOf(input)
-	if inputValue.Kind() != reflect.Map {
-		return fmt.Errorf(notMapInputMsg, inputValue.Type().String())
+	return NewDecoder(tags...).MapToMap(input, receiver)
+}
+
+// StructToMap provides the reverse of MapToStruct, returning a map[string]interface{} populated from a struct with the
+// option of passing alternative struct tags to use as map keys. If no tags are specified the json tag is used and if
+// that is not present, the struct field name is used. Unexported fields are skipped.
+//
+// The input argument must be a struct or a pointer to a struct.
+//
+// Nested structs are converted to map[string]interface{}, slices and arrays to []interface{} and maps to
+// map[string]interface{}, with numeric map keys formatted as strings. Values of types registered with RegisterEnum or
+// RegisterEnumStringer are converted to their names.
+func StructToMap(input interface{}, tags ...string) (map[string]interface{}, error) {
+	return NewDecoder(tags...).StructToMap(input)
+}
+
+// StructToTuple provides the reverse of the population of a struct from a tuple, returning a []interface{} of the
+// values of the exported fields in order, or of the fields with pos tags at their positions, with the option of
+// passing alternative struct tags to use as map keys for nested structs. Values are converted as StructToMap converts
+// them.
+//
+// A struct is populated from a tuple, that is a slice or an array such as [213, "Zhaoliu", "male"], wherever a
+// struct is populated from a map, including by SliceToSlice and Convert for rows of tuples. If any field has a pos
+// tag, for example `pos:"2"`, only the fields with pos tags are populated, from the values at their positions.
+//
+// The input argument must be a struct or a pointer to a struct.
+func StructToTuple(input interface{}, tags ...string) ([]interface{}, error) {
+	return NewDecoder(tags...).StructToTuple(input)
+}
+
+// StructToStruct populates a struct from a struct of another type, such as a domain type from an API DTO, with the
+// option of passing alternative struct tags to use as map keys. Fields are matched by the keys given by the tags of
+// both structs, as they would be by StructToMap followed by MapToStruct, with nested types converted recursively and
+// the usual type conversions applied.
+//
+// The input argument must be a struct or a pointer to a struct.
+//
+// The receiver argument must be a pointer to a struct.
+//
+// Destination fields not matched by a source field are left unchanged, unless the ErrorUnmapped setting of a Decoder
+// is used to report them as an error.
+func StructToStruct(input interface{}, receiver interface{}, tags ...string) error {
+	return NewDecoder(tags...).StructToStruct(input, receiver)
+}
+
+// StructsToStructs populates a slice of structs from a slice of structs of another type as StructToStruct does for
+// each element.
+//
+// The input argument must be a slice or an array of structs, or of pointers to structs.
+//
+// The receiver argument must be a pointer to a slice of structs, or of pointers to structs.
+func StructsToStructs(input interface{}, receiver interface{}, tags ...string) error {
+	return NewDecoder(tags...).StructsToStructs(input, receiver)
+}
+
+// Convert populates the receiver from an input of any kind, dispatching to whichever of the conversions of the other
+// functions applies, with the option of passing alternative struct tags to use as map keys. This allows, for example,
+// a struct to populate a map or a struct of another type, and a []interface{} of maps to populate a slice of structs.
+//
+// A slice or an array populates a map keyed by its indexes, such as map[int]User or map[string]User. Slices nested in
+// the input only populate maps in this way with the SlicesToIndexMaps setting of a Decoder.
+//
+// The receiver argument must be a non-nil pointer.
+//
+// A nil input leaves the receiver unchanged.
+func Convert(input interface{}, receiver interface{}, tags ...string) error {
+	return NewDecoder(tags...).Convert(input, receiver)
+}
+
+// Normalize deep-converts a tree of maps and slices, such as is produced by YAML or msgpack decoders, so that every map
+// becomes a map[string]interface{} and every slice a []interface{}. Map keys must be strings, or numbers which are
+// formatted as strings, either directly or held in interfaces. Other values are returned unchanged.
+//
+// The conversion functions accept maps with interface keys holding strings as struct sources without normalizing,
+// but Normalize allows such trees to be used wherever a map[string]interface{} is required.
+func Normalize(input interface{}) (interface{}, error) {
+	return normalize(reflect.ValueOf(input))
+}
+
+// Flatten returns a map of the leaves of a tree of maps and slices keyed by their paths, with the parts of each path
+// joined by the separator, so that for example {"location": {"city": "London"}, "orders": [{"id": 1}]} becomes
+// {"location.city": "London", "orders.0.id": 1}. Empty maps and slices are kept as leaves.
+//
+// If the separator is empty, paths could not be split again, so the output is a copy of the input without flattening.
+func Flatten(input map[string]interface{}, separator string) map[string]interface{} {
+	output := make(map[string]interface{}, len(input))
+	if separator == "" {
+		for key, value := range input {
+			output[key] = value
+		}
+		return output
 	}
+	flatten("", reflect.ValueOf(input), separator, output)
+	return output
+}
 
-	return setMap(reflect.ValueOf(receiver).Elem(), inputValue, tags)
+// Unflatten provides the reverse of Flatten, nesting the values of keys containing the separator in maps keyed by the
+// parts of the keys. Indexes are kept as map keys, which are converted to slice indexes when populating a slice with
+// the IndexMapsToSlices or Separator settings of a Decoder. The input is not modified.
+//
+// It is an error for a key to pass through a value which is not a map, such as "a.b" when "a" is a string, or for the
+// separator to be empty.
+func Unflatten(input map[string]interface{}, separator string) (map[string]interface{}, error) {
+	return unflatten(input, separator)
 }
@@ -608,3 +608,307 @@ func TestInputs(t *testing.T) {
 		assert.Equal(t, "the input argument must be a map but a string was given", err.Error(), "error message should be identify cause")
 	}
 }
+
+func TestStructToMap(t *testing.T) {
+	age := 19
+	in := UserWithPointers{
+		ID:     213,
+		Name:   "Zhaoliu",
+		Age:    &age,
+		Sports: &[]string{"football", "tennis"},
+		Location: &Location{
+			Country: "UK",
+			City:    "London",
+		},
+	}
+
+	out, err := mapstostructs.StructToMap(&in)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, 213, out["id"], "values should be correctly set")
+		assert.Equal(t, 19, out["age"], "pointers should be dereferenced")
+		assert.Equal(t, []interface{}{"football", "tennis"}, out["sports"], "slices should be converted")
+		assert.Equal(t, map[string]interface{}{"country": "UK", "city": "London"}, out["location"], "structs should be converted")
+	}
+
+	var user UserWithPointers
+
+	err = mapstostructs.MapToStruct(out, &user)
+
+	if assert.Nil(t, err, "the output should convert back") {
+		assert.Equal(t, in, user, "the round trip should preserve values")
+	}
+
+	out, err = mapstostructs.StructToMap(UserWithTags{Gender: "female"}, "alias")
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, "female", out["sex"], "alternative tags should be used as keys")
+		assert.Equal(t, 0, out["Age"], "field names should be used as keys without tags")
+	}
+
+	out, err = mapstostructs.StructToMap(Recursor1{IntMap1: map[int]Recursor2{1: {}}})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Contains(t, out["intMap1"], "1", "numeric map keys should be formatted as strings")
+	}
+}
+
+func TestStructToMapOmitted(t *testing.T) {
+	type Account struct {
+		ID       int    `json:"id"`
+		Password string `json:"-"`
+		Dash     string `json:"-,"`
+	}
+
+	out, err := mapstostructs.StructToMap(Account{ID: 213, Password: "secret", Dash: "dash"})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{"id": 213, "-": "dash"}, out, "fields tagged - should be omitted")
+	}
+
+	columns, err := mapstostructs.StructsToColumns([]Account{{ID: 213, Password: "secret"}})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.NotContains(t, columns, "Password", "fields tagged - should have no column")
+		assert.Equal(t, []interface{}{213}, columns["id"], "other fields should have columns")
+	}
+
+	var account Account
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"id": 56, "-": "dash", "password": "secret"}, &account)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Account{ID: 56, Dash: "dash"}, account, "fields tagged - should not be matched by the key -")
+	}
+
+	var copied Account
+
+	err = mapstostructs.StructToStruct(Account{ID: 56, Password: "secret"}, &copied)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Account{ID: 56}, copied, "fields tagged - should not be copied")
+	}
+}
+
+func TestStructToMapBadInput(t *testing.T) {
+	_, err := mapstostructs.StructToMap("test")
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid input") {
+		assert.Equal(t, "the input argument must be a struct or a ptr to a struct but a string was given", err.Error())
+	}
+
+	test := "test"
+	_, err = mapstostructs.StructToMap(&test)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid input") {
+		assert.Equal(t, "the input argument must be a struct or a ptr to a struct but a ptr to a string was given", err.Error())
+	}
+
+	_, err = mapstostructs.StructToMap(struct{ M map[[2]int]int }{M: map[[2]int]int{{1, 2}: 1}})
+
+	if assert.NotNil(t, err, "error should not be nil with an unformattable map key") {
+		assert.Equal(t, "the M field for a struct of type  the map key for a map[[2]int]int must be or be convertible to string type, but received '[1 2]'", err.Error())
+	}
+}
+
+func TestMapsToStructsPointerElements(t *testing.T) {
+	maps := []map[string]interface{}{
+		{"id": 213, "name": "Zhaoliu", "location": map[string]interface{}{"country": "UK"}},
+		{"id": 56, "name": "Zhangsan", "age": nil},
+	}
+
+	var users []*User
+
+	err := mapstostructs.MapsToStructs(maps, &users)
+
+	if assert.Nil(t, err, "error should be nil for a slice of pointers") {
+		if assert.Equal(t, 2, len(users), "all rows should be returned") {
+			assert.Equal(t, "UK", users[0].Location.Country, "values should be correctly set")
+			assert.Equal(t, 56, users[1].ID, "values should be correctly set")
+			assert.Equal(t, 0, users[1].Age, "nil values should be ignored")
+		}
+	}
+
+	var pointers []**User
+
+	err = mapstostructs.MapsToStructs(maps, &pointers)
+
+	if assert.Nil(t, err, "error should be nil for a slice of pointers to pointers") {
+		if assert.Equal(t, 2, len(pointers), "all rows should be returned") {
+			assert.Equal(t, "Zhaoliu", (*pointers[0]).Name, "values should be correctly set")
+		}
+	}
+
+	var strings []*string
+
+	err = mapstostructs.MapsToStructs(maps, &strings)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid receiver") {
+		expected := "the receiver argument must be a ptr to a slice of struct but a ptr to a slice of ptr to a string was given"
+		assert.Equal(t, expected, err.Error(), "the error string should describe the receiver")
+	}
+}
+
+func TestSliceToSlice(t *testing.T) {
+	var ints []int
+
+	err := mapstostructs.SliceToSlice([]interface{}{1, float64(2), nil}, &ints)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []int{1, 2, 0}, ints, "values should be converted")
+	}
+
+	var nested [][]string
+
+	err = mapstostructs.SliceToSlice([]interface{}{[]interface{}{"a", "b"}, [1]string{"c"}}, &nested)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, nested, "nested slices and arrays should be converted")
+	}
+
+	var locations []map[string]*Location
+
+	err = mapstostructs.SliceToSlice([]interface{}{
+		map[string]interface{}{"home": map[string]interface{}{"city": "London"}},
+	}, &locations)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, "London", locations[0]["home"].City, "maps of structs should be converted")
+	}
+
+	var holder struct{ P **[]int }
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"p": []interface{}{7}}, &holder)
+
+	if assert.Nil(t, err, "error should be nil for nested pointers") {
+		assert.Equal(t, []int{7}, **holder.P, "each level of pointer should be populated")
+	}
+
+	err = mapstostructs.SliceToSlice([]interface{}{"x"}, &ints)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "must be or be convertible to int type, but received 'x' in row 1", err.Error())
+	}
+
+	err = mapstostructs.SliceToSlice("x", &ints)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid input") {
+		assert.Equal(t, "the input argument must be a slice or an array but a string was given", err.Error())
+	}
+
+	err = mapstostructs.SliceToSlice([]int{}, ints)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid receiver") {
+		assert.Equal(t, "the receiver argument must be a ptr to a slice but a slice was given", err.Error())
+	}
+}
+
+func TestConvert(t *testing.T) {
+	in := User{ID: 213, Name: "Zhaoliu", Location: Location{Country: "UK", City: "London"}}
+
+	var out map[string]interface{}
+
+	err := mapstostructs.Convert(in, &out)
+
+	if assert.Nil(t, err, "error should be nil for a struct to a map") {
+		assert.Equal(t, 213, out["id"], "values should be correctly set")
+		assert.Equal(t, map[string]interface{}{"country": "UK", "city": "London"}, out["location"], "nested structs should be converted")
+	}
+
+	var withTags UserWithTags
+
+	err = mapstostructs.Convert(&in, &withTags)
+
+	if assert.Nil(t, err, "error should be nil for a struct to a struct") {
+		assert.Equal(t, UserWithTags{ID: 213, Name: "Zhaoliu"}, withTags, "matching fields should be correctly set")
+	}
+
+	var cities map[string]string
+
+	err = mapstostructs.Convert(in.Location, &cities)
+
+	if assert.Nil(t, err, "error should be nil for a struct to a typed map") {
+		assert.Equal(t, map[string]string{"country": "UK", "city": "London"}, cities, "values should be correctly set")
+	}
+
+	var users []User
+
+	err = mapstostructs.Convert([]interface{}{
+		map[string]interface{}{"id": 213},
+		map[string]interface{}{"id": 56},
+	}, &users)
+
+	if assert.Nil(t, err, "error should be nil for a slice of maps to a slice of structs") {
+		assert.Equal(t, []User{{ID: 213}, {ID: 56}}, users, "values should be correctly set")
+	}
+
+	var number *int
+
+	err = mapstostructs.Convert(float64(5), &number)
+
+	if assert.Nil(t, err, "error should be nil for a scalar") {
+		assert.Equal(t, 5, *number, "values should be converted")
+	}
+
+	err = mapstostructs.Convert(nil, &number)
+
+	if assert.Nil(t, err, "error should be nil for a nil input") {
+		assert.Equal(t, 5, *number, "a nil input should leave the receiver unchanged")
+	}
+
+	err = mapstostructs.Convert("invalid", &users)
+
+	if assert.NotNil(t, err, "error should not be nil with an unsupported pair") {
+		assert.Equal(t, "must be or be convertible to []mapstostructs_test.User type, but received 'invalid'", err.Error())
+	}
+}
+
+func TestConvertSliceToMap(t *testing.T) {
+	var byIndex map[string]User
+
+	err := mapstostructs.Convert([]interface{}{User{ID: 3}, map[string]interface{}{"id": 4}}, &byIndex)
+
+	if assert.Nil(t, err, "error should be nil for a slice to a map") {
+		assert.Equal(t, map[string]User{"0": {ID: 3}, "1": {ID: 4}}, byIndex, "elements should be keyed by their indexes")
+	}
+
+	var byInt *map[int]string
+
+	err = mapstostructs.Convert([2]string{"a", "b"}, &byInt)
+
+	if assert.Nil(t, err, "error should be nil for an array to a map") {
+		assert.Equal(t, map[int]string{0: "a", 1: "b"}, *byInt, "elements should be keyed by their indexes")
+	}
+
+	var nested map[string]map[string]int
+
+	err = mapstostructs.Convert(map[string]interface{}{"a": []int{1}}, &nested)
+
+	assert.NotNil(t, err, "nested slices should not populate maps without SlicesToIndexMaps")
+}
+
+func TestConvertBadReceiver(t *testing.T) {
+	err := mapstostructs.Convert(1, 1)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid receiver") {
+		assert.Equal(t, "the receiver argument must be a non-nil ptr but a int was given", err.Error())
+	}
+
+	var user *User
+
+	err = mapstostructs.Convert(map[string]interface{}{}, user)
+
+	if assert.NotNil(t, err, "error should not be nil with a nil receiver") {
+		assert.Equal(t, "the receiver argument must be a non-nil ptr but a nil ptr was given", err.Error())
+	}
+}
+
+func TestMapToMapBadKeyWithPercent(t *testing.T) {
+	var byID map[int]string
+
+	err := mapstostructs.MapToMap(map[string]interface{}{"5%d": "x"}, &byID)
+
+	if assert.NotNil(t, err, "error should not be nil for an unconvertible key") {
+		assert.Equal(t, "the map key for a map[int]string must be or be convertible to int type, but received '5%d'", err.Error())
+	}
+}
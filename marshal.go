@@ -0,0 +1,61 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const (
+	unmarshalMapMsg = "cannot be unmarshalled as %s type: %s"
+	marshalMapMsg   = "cannot be marshalled from %s type: %s"
+)
+
+// MapUnmarshaler is implemented by types with their own map representation, such as a Money struct given as
+// {"amount": "1.20", "ccy": "GBP"} or as "GBP 1.20". UnmarshalMap is called with the input value, which may be of
+// any type, in place of the usual conversion, at any depth including map values and slice elements.
+type MapUnmarshaler interface {
+	UnmarshalMap(input interface{}) error
+}
+
+// MapMarshaler is implemented by types with their own map representation. StructToMap and the other reverse
+// conversions output the value returned by MarshalMap in place of the usual conversion, at any depth.
+type MapMarshaler interface {
+	MarshalMap() (interface{}, error)
+}
+
+var (
+	mapUnmarshalerType = reflect.TypeOf((*MapUnmarshaler)(nil)).Elem()
+	mapMarshalerType   = reflect.TypeOf((*MapMarshaler)(nil)).Elem()
+)
+
+// unmarshalMap populates a new value of a type implementing MapUnmarshaler from the input.
+func unmarshalMap(input reflect.Value, wantType reflect.Type) (reflect.Value, error) {
+	newValue := reflect.New(wantType)
+	if err := newValue.Interface().(MapUnmarshaler).UnmarshalMap(input.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf(unmarshalMapMsg, wantType.String(), err.Error())
+	}
+	return newValue.Elem(), nil
+}
+
+// marshalMap returns the result of MarshalMap for a value whose type, or pointer type, implements MapMarshaler, and
+// whether it does.
+func marshalMap(input reflect.Value) (interface{}, bool, error) {
+	inputType := input.Type()
+	switch {
+	case input.Type().Implements(mapMarshalerType):
+	case reflect.PtrTo(input.Type()).Implements(mapMarshalerType):
+		if !input.CanAddr() {
+			copied := reflect.New(input.Type()).Elem()
+			copied.Set(input)
+			input = copied
+		}
+		input = input.Addr()
+	default:
+		return nil, false, nil
+	}
+	output, err := input.Interface().(MapMarshaler).MarshalMap()
+	if err != nil {
+		return nil, true, fmt.Errorf(marshalMapMsg, inputType.String(), err.Error())
+	}
+	return output, true, nil
+}
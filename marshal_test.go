@@ -0,0 +1,128 @@
+package mapstostructs_test
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Money struct {
+	Pence    int64
+	Currency string
+}
+
+func (m *Money) UnmarshalMap(input interface{}) error {
+	var amount, currency string
+	switch input := input.(type) {
+	case string:
+		parts := strings.Fields(input)
+		if len(parts) != 2 {
+			return fmt.Errorf("'%s' is not a currency and an amount", input)
+		}
+		currency, amount = parts[0], parts[1]
+	case map[string]interface{}:
+		amount, _ = input["amount"].(string)
+		currency, _ = input["ccy"].(string)
+	default:
+		return fmt.Errorf("%T is not a string or a map", input)
+	}
+	pounds, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return fmt.Errorf("'%s' is not an amount", amount)
+	}
+	m.Pence, m.Currency = int64(pounds*100+0.5), currency
+	return nil
+}
+
+func (m Money) MarshalMap() (interface{}, error) {
+	if m.Pence < 0 {
+		return nil, fmt.Errorf("%d is negative", m.Pence)
+	}
+	return map[string]interface{}{"amount": fmt.Sprintf("%d.%02d", m.Pence/100, m.Pence%100), "ccy": m.Currency}, nil
+}
+
+type Basket struct {
+	Total  Money            `json:"total"`
+	Items  []*Money         `json:"items"`
+	ByName map[string]Money `json:"byName"`
+}
+
+func TestMapUnmarshaler(t *testing.T) {
+	input := map[string]interface{}{
+		"total":  map[string]interface{}{"amount": "1.20", "ccy": "GBP"},
+		"items":  []interface{}{"GBP 0.20", map[string]interface{}{"amount": "1", "ccy": "GBP"}},
+		"byName": map[string]interface{}{"tea": "GBP 0.20"},
+	}
+
+	var basket Basket
+
+	err := mapstostructs.MapToStruct(input, &basket)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Basket{
+			Total:  Money{Pence: 120, Currency: "GBP"},
+			Items:  []*Money{{Pence: 20, Currency: "GBP"}, {Pence: 100, Currency: "GBP"}},
+			ByName: map[string]Money{"tea": {Pence: 20, Currency: "GBP"}},
+		}, basket, "UnmarshalMap should be called at any depth")
+	}
+}
+
+func TestMapMarshaler(t *testing.T) {
+	basket := Basket{
+		Total:  Money{Pence: 120, Currency: "GBP"},
+		Items:  []*Money{{Pence: 20, Currency: "GBP"}, {Pence: 100, Currency: "GBP"}},
+		ByName: map[string]Money{"tea": {Pence: 20, Currency: "GBP"}},
+	}
+
+	output, err := mapstostructs.StructToMap(basket)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{
+			"total": map[string]interface{}{"amount": "1.20", "ccy": "GBP"},
+			"items": []interface{}{
+				map[string]interface{}{"amount": "0.20", "ccy": "GBP"},
+				map[string]interface{}{"amount": "1.00", "ccy": "GBP"},
+			},
+			"byName": map[string]interface{}{"tea": map[string]interface{}{"amount": "0.20", "ccy": "GBP"}},
+		}, output, "MarshalMap should be called at any depth")
+	}
+}
+
+func TestMapUnmarshalerError(t *testing.T) {
+	var basket Basket
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"items": []interface{}{"GBP"}}, &basket)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the Items field for a struct of type Basket cannot be unmarshalled as mapstostructs_test.Money type: 'GBP' is not a currency and an amount in row 1", err.Error())
+	}
+}
+
+func TestMapMarshalerError(t *testing.T) {
+	_, err := mapstostructs.StructToMap(Basket{Total: Money{Pence: -1}})
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the Total field for a struct of type Basket cannot be marshalled from mapstostructs_test.Money type: -1 is negative", err.Error())
+	}
+}
+
+func TestMapMarshalersStructToStruct(t *testing.T) {
+	basket := Basket{
+		Total:  Money{Pence: 120, Currency: "GBP"},
+		Items:  []*Money{{Pence: 20, Currency: "GBP"}},
+		ByName: map[string]Money{"tea": {Pence: 20, Currency: "GBP"}},
+	}
+
+	var copied Basket
+
+	err := mapstostructs.StructToStruct(basket, &copied)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, basket, copied, "values should round trip through their map representations")
+	}
+}
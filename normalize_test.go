@@ -0,0 +1,98 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+func TestInterfaceKeyedMapToStruct(t *testing.T) {
+	input := map[interface{}]interface{}{
+		"id":       213,
+		"name":     "Zhaoliu",
+		"location": map[interface{}]interface{}{"country": "UK", "city": "London"},
+	}
+
+	var user User
+
+	err := mapstostructs.Convert(input, &user)
+
+	if assert.Nil(t, err, "error should be nil for maps with interface keys") {
+		assert.Equal(t, 213, user.ID, "values should be correctly set")
+		assert.Equal(t, "London", user.Location.City, "nested maps with interface keys should be converted")
+	}
+}
+
+func TestInterfaceKeyedMapsToStructs(t *testing.T) {
+	input := []interface{}{
+		map[interface{}]interface{}{"id": 213, "location": map[interface{}]interface{}{"country": "UK"}},
+	}
+
+	var users []User
+
+	err := mapstostructs.SliceToSlice(input, &users)
+
+	if assert.Nil(t, err, "error should be nil for maps with interface keys") {
+		assert.Equal(t, []User{{ID: 213, Location: Location{Country: "UK"}}}, users, "values should be correctly set")
+	}
+}
+
+func TestInterfaceKeyedMapToMap(t *testing.T) {
+	var byName map[string]interface{}
+
+	err := mapstostructs.Convert(map[interface{}]interface{}{"name": "Zhaoliu"}, &byName)
+
+	if assert.Nil(t, err, "error should be nil for interface keys into string keys") {
+		assert.Equal(t, map[string]interface{}{"name": "Zhaoliu"}, byName, "values should be correctly set")
+	}
+}
+
+func TestInterfaceKeyedMapBadKeys(t *testing.T) {
+	var user User
+
+	err := mapstostructs.Convert(map[interface{}]interface{}{"id": 1, 2: "two"}, &user)
+
+	if assert.NotNil(t, err, "error should not be nil for non-string keys") {
+		assert.Equal(t, "the map keys for a struct of type User must be strings, but received int '2'", err.Error())
+	}
+
+	err = mapstostructs.Convert(map[string]interface{}{"location": map[interface{}]interface{}{true: 1}}, &user)
+
+	if assert.NotNil(t, err, "error should not be nil for non-string keys") {
+		assert.Equal(t, "the Location field for a struct of type User the map keys for a struct of type Location must be strings, but received bool 'true'", err.Error())
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	input := []interface{}{
+		map[interface{}]interface{}{
+			"id":       213,
+			"sports":   []interface{}{"football", "tennis"},
+			"location": map[interface{}]interface{}{"city": "London"},
+		},
+		map[interface{}]interface{}{1: []string{"a"}},
+	}
+
+	out, err := mapstostructs.Normalize(input)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{
+				"id":       213,
+				"sports":   []interface{}{"football", "tennis"},
+				"location": map[string]interface{}{"city": "London"},
+			},
+			map[string]interface{}{"1": []interface{}{"a"}},
+		}, out, "maps and slices should be normalized")
+	}
+}
+
+func TestNormalizeBadKeys(t *testing.T) {
+	_, err := mapstostructs.Normalize(map[interface{}]interface{}{"a": map[interface{}]interface{}{[2]int{1, 2}: 1}})
+
+	if assert.NotNil(t, err, "error should not be nil for unformattable keys") {
+		assert.Equal(t, "the map value for a map[interface {}]interface {} the map key for a map[interface {}]interface {} must be or be convertible to string type, but received '[1 2]'", err.Error())
+	}
+}
@@ -0,0 +1,99 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	pathTag         = "path"
+	pathSeparator   = "."
+	pathConflictMsg = "the path '%s' for the %s field for a struct of type %s conflicts with another value"
+)
+
+// pathFields returns the path parts for each field of a struct type with a path tag, keyed by field index.
+func pathFields(structType reflect.Type) map[int][]string {
+	var paths map[int][]string
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if tag, ok := field.Tag.Lookup(pathTag); ok && field.PkgPath == "" {
+			if paths == nil {
+				paths = make(map[int][]string)
+			}
+			paths[i] = strings.Split(tag, pathSeparator)
+		}
+	}
+	return paths
+}
+
+// resolvePath walks a path through nested maps, matching keys as map keys are matched to struct fields, and through
+// slices and arrays by index. The result is false if any part of the path is missing.
+func resolvePath(input reflect.Value, parts []string) (reflect.Value, bool) {
+	current := input
+	for _, part := range parts {
+		for current.Kind() == reflect.Ptr || current.Kind() == reflect.Interface {
+			current = current.Elem()
+		}
+		switch current.Kind() {
+
+		case reflect.Map:
+			var found bool
+			mapRange := current.MapRange()
+			for mapRange.Next() {
+				key := mapRange.Key()
+				if key.Kind() == reflect.Interface {
+					key = key.Elem()
+				}
+				if keyString, ok := formatMapKey(key); ok && strings.EqualFold(keyString, part) {
+					current, found = mapRange.Value(), true
+					break
+				}
+			}
+			if !found {
+				return reflect.Value{}, false
+			}
+
+		case reflect.Slice, reflect.Array:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= current.Len() {
+				return reflect.Value{}, false
+			}
+			current = current.Index(index)
+
+		default:
+			return reflect.Value{}, false
+		}
+	}
+	return current, true
+}
+
+// insertPath sets a value in nested maps keyed by the parts of a path, creating the maps as needed. The result is
+// false if the path passes through, or ends at, an existing value which is not a map[string]interface{}.
+func insertPath(output map[string]interface{}, parts []string, value interface{}) bool {
+	current := output
+	for _, part := range parts[:len(parts)-1] {
+		next, exists := current[part]
+		if !exists {
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		nested, ok := next.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		current = nested
+	}
+	last := parts[len(parts)-1]
+	if _, exists := current[last]; exists {
+		return false
+	}
+	current[last] = value
+	return true
+}
+
+func pathConflictError(structType reflect.Type, fieldIndex int, parts []string) error {
+	return fmt.Errorf(pathConflictMsg, strings.Join(parts, pathSeparator), structType.Field(fieldIndex).Name,
+		structType.Name())
+}
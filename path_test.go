@@ -0,0 +1,85 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Place struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `path:"geo.coords.lat"`
+	Longitude float64 `path:"geo.coords.lng"`
+	FirstTag  string  `path:"tags.0"`
+}
+
+type BadPlace struct {
+	Geo      string  `json:"geo"`
+	Latitude float64 `path:"geo.lat"`
+}
+
+func TestPathTag(t *testing.T) {
+	input := map[string]interface{}{
+		"name": "Greenwich",
+		"geo": map[string]interface{}{
+			"coords": map[string]interface{}{"LAT": 51.48, "lng": -0.0015},
+		},
+		"tags": []interface{}{"observatory", "park"},
+	}
+
+	var place Place
+
+	err := mapstostructs.MapToStruct(input, &place)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Place{Name: "Greenwich", Latitude: 51.48, Longitude: -0.0015, FirstTag: "observatory"}, place)
+	}
+
+	output, err := mapstostructs.StructToMap(place)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{
+			"name": "Greenwich",
+			"geo": map[string]interface{}{
+				"coords": map[string]interface{}{"lat": 51.48, "lng": -0.0015},
+			},
+			"tags": map[string]interface{}{"0": "observatory"},
+		}, output, "paths should be rebuilt as nested maps")
+	}
+
+	place = Place{}
+	err = mapstostructs.MapToStruct(map[string]interface{}{"geo": map[string]interface{}{}, "tags": []string{}}, &place)
+
+	if assert.Nil(t, err, "error should be nil for missing paths") {
+		assert.Equal(t, Place{}, place, "missing paths should leave fields unset")
+	}
+
+	decoder := &mapstostructs.Decoder{ErrorUnmapped: true}
+	err = decoder.MapToStruct(map[string]interface{}{"name": "x", "geo": map[string]interface{}{"coords": 1}}, &place)
+
+	if assert.NotNil(t, err, "error should not be nil with missing paths and ErrorUnmapped") {
+		assert.Equal(t, "the Latitude, Longitude, FirstTag field(s) for a struct of type Place had no input to map from", err.Error())
+	}
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"geo": map[string]interface{}{"coords": map[string]interface{}{"lat": "north"}}}, &place)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the Latitude field for a struct of type Place must be or be convertible to float64 type, but received 'north'", err.Error())
+	}
+
+	_, err = mapstostructs.StructToMap(BadPlace{Geo: "x", Latitude: 1})
+
+	if assert.NotNil(t, err, "error should not be nil with conflicting paths") {
+		assert.Equal(t, "the path 'geo.lat' for the Latitude field for a struct of type BadPlace conflicts with another value", err.Error())
+	}
+}
+
+func TestPathTagColumns(t *testing.T) {
+	columns, err := mapstostructs.StructsToColumns([]Place{})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{"name": []interface{}{}, "geo": []interface{}{}, "tags": []interface{}{}}, columns, "path fields should give columns for the first parts of their paths")
+	}
+}
@@ -0,0 +1,54 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	prefixTag         = "prefix"
+	prefixConflictMsg = "the key '%s' for the %s field for a struct of type %s conflicts with another value"
+)
+
+// prefixField is a struct field populated from the keys with its prefix, such as billing_city for billing_.
+type prefixField struct {
+	index  int
+	prefix string
+}
+
+// prefixFields returns the fields of a struct type with a prefix tag, in field order.
+func prefixFields(structType reflect.Type) []prefixField {
+	var fields []prefixField
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if tag, ok := field.Tag.Lookup(prefixTag); ok && tag != "" && field.PkgPath == "" {
+			fields = append(fields, prefixField{index: i, prefix: tag})
+		}
+	}
+	return fields
+}
+
+// matchPrefix returns the position in the fields of the first with a prefix which the key has, ignoring case, and
+// the key without the prefix.
+func matchPrefix(fields []prefixField, key string) (int, string, bool) {
+	for i, field := range fields {
+		if len(key) > len(field.prefix) && strings.EqualFold(key[:len(field.prefix)], field.prefix) {
+			return i, key[len(field.prefix):], true
+		}
+	}
+	return 0, "", false
+}
+
+// insertPrefixed adds the keys and values of a nested struct's map to the output with the prefix, which is empty for
+// a remain field.
+func insertPrefixed(output map[string]interface{}, field prefixField, nested map[string]interface{},
+	structType reflect.Type) error {
+	for key, nestedValue := range nested {
+		if _, exists := output[field.prefix+key]; exists {
+			return fmt.Errorf(prefixConflictMsg, field.prefix+key, structType.Field(field.index).Name, structType.Name())
+		}
+		output[field.prefix+key] = nestedValue
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Address struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+type Contact struct {
+	Name  string   `json:"name"`
+	Phone *Address `json:"phone" prefix:"phone_"`
+}
+
+type Invoice struct {
+	ID       int      `json:"id"`
+	Billing  Address  `prefix:"billing_"`
+	Shipping *Address `prefix:"shipping_"`
+	Contact  Contact  `prefix:"contact_"`
+}
+
+type BadInvoice struct {
+	BillingCity string  `json:"billing_city"`
+	Billing     Address `prefix:"billing_"`
+}
+
+func TestPrefixTag(t *testing.T) {
+	input := map[string]interface{}{
+		"id":                 1,
+		"billing_city":       "London",
+		"BILLING_COUNTRY":    "UK",
+		"contact_name":       "Zhaoliu",
+		"contact_phone_city": "Paris",
+	}
+
+	var invoice Invoice
+
+	err := mapstostructs.MapToStruct(input, &invoice)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Invoice{
+			ID:      1,
+			Billing: Address{City: "London", Country: "UK"},
+			Contact: Contact{Name: "Zhaoliu", Phone: &Address{City: "Paris"}},
+		}, invoice, "prefixed keys should populate nested structs")
+	}
+}
+
+func TestPrefixTagStructToMap(t *testing.T) {
+	invoice := Invoice{
+		ID:      1,
+		Billing: Address{City: "London", Country: "UK"},
+		Contact: Contact{Name: "Zhaoliu", Phone: &Address{City: "Paris"}},
+	}
+
+	output, err := mapstostructs.StructToMap(invoice)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{
+			"id":                    1,
+			"billing_city":          "London",
+			"billing_country":       "UK",
+			"contact_name":          "Zhaoliu",
+			"contact_phone_city":    "Paris",
+			"contact_phone_country": "",
+		}, output, "nested structs should be flattened with their prefixes")
+	}
+}
+
+func TestPrefixTagBadValue(t *testing.T) {
+	var invoice Invoice
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"shipping_city": []int{1}}, &invoice)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the Shipping field for a struct of type Invoice the City field for a struct of type Address must be or be convertible to string type, but received '[1]'", err.Error())
+	}
+}
+
+func TestPrefixTagConflict(t *testing.T) {
+	_, err := mapstostructs.StructToMap(BadInvoice{BillingCity: "x"})
+
+	if assert.NotNil(t, err, "error should not be nil with conflicting keys") {
+		assert.Equal(t, "the key 'billing_city' for the Billing field for a struct of type BadInvoice conflicts with another value", err.Error())
+	}
+}
@@ -0,0 +1,202 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	readonlyOption  = "readonly"
+	protectedMsg    = "the key(s) %s for a struct of type %s are protected"
+	protectedMapMsg = "the key(s) %s for a %s are protected"
+)
+
+// ProtectedKeyPolicy sets how keys for protected fields are handled: fields with the readonly tag option, fields
+// outside the AllowFields setting and fields within the DenyFields setting.
+type ProtectedKeyPolicy int
+
+const (
+	// ProtectedKeysSkip ignores keys for protected fields.
+	ProtectedKeysSkip ProtectedKeyPolicy = iota
+	// ProtectedKeysError returns an error naming the keys for protected fields, before any field is populated.
+	ProtectedKeysError
+)
+
+// hasTagOption reports whether the first of the tags present on an exported struct field has the option, such as
+// remain in json:",remain".
+func hasTagOption(field reflect.StructField, tags []string, option string) bool {
+	if field.PkgPath != "" {
+		return false
+	}
+	tag, ok := lookupTag(field, tags)
+	if !ok {
+		return false
+	}
+	for _, tagOption := range strings.Split(tag, ",")[1:] {
+		if tagOption == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isReadonly reports whether any of the tags on an exported struct field, including the json tag, has the readonly
+// option, so that a field marked readonly in one tag cannot be populated through another.
+func isReadonly(field reflect.StructField, tags []string) bool {
+	if field.PkgPath != "" {
+		return false
+	}
+	for _, tagName := range append(tags[:len(tags):len(tags)], jsonTag) {
+		tag, ok := field.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		for _, tagOption := range strings.Split(tag, ",")[1:] {
+			if tagOption == readonlyOption {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldPath returns the path of a key within the struct being populated, such as profile.role, in lower case.
+func (d *Decoder) fieldPath(key string) string {
+	if d.path == "" {
+		return strings.ToLower(key)
+	}
+	return d.path + pathSeparator + strings.ToLower(key)
+}
+
+// at returns the Decoder to use for the value of a key, which tracks the path of the key if the AllowFields or
+// DenyFields settings need it.
+func (d *Decoder) at(key string) *Decoder {
+	if len(d.AllowFields) == 0 && len(d.DenyFields) == 0 {
+		return d
+	}
+	child := *d
+	child.path = d.fieldPath(key)
+	return &child
+}
+
+// protectedPath reports whether the path of a key is within the DenyFields setting or outside the AllowFields
+// setting. A path is allowed if it is within an allowed path or passes through one.
+func (d *Decoder) protectedPath(key string) bool {
+	if len(d.AllowFields) == 0 && len(d.DenyFields) == 0 {
+		return false
+	}
+	path := d.fieldPath(key)
+	for _, denied := range d.DenyFields {
+		if withinPath(path, strings.ToLower(denied)) {
+			return true
+		}
+	}
+	if len(d.AllowFields) == 0 {
+		return false
+	}
+	for _, allowed := range d.AllowFields {
+		allowed = strings.ToLower(allowed)
+		if withinPath(path, allowed) || withinPath(allowed, path) {
+			return false
+		}
+	}
+	return true
+}
+
+// withinPath reports whether a path is the base path or below it.
+func withinPath(path string, base string) bool {
+	return path == base || strings.HasPrefix(path, base+pathSeparator)
+}
+
+// protectedFields returns the names of the protected exported fields of a struct type, other than the inactive
+// fields, given the names of its readonly fields, or nil if there are none.
+func (d *Decoder) protectedFields(structType reflect.Type, readonly map[string]bool,
+	inactive map[string]bool) map[string]bool {
+	if len(d.AllowFields) == 0 && len(d.DenyFields) == 0 && inactive == nil {
+		return readonly
+	}
+	var protected map[string]bool
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" || inactive[field.Name] {
+			continue
+		}
+		// The keys collected by a remain field are checked individually, as they are keys of the struct itself.
+		if readonly[field.Name] || !hasRemainOption(field, d.Tags) && d.protectedPath(fieldKey(field, d.Tags)) {
+			if protected == nil {
+				protected = make(map[string]bool)
+			}
+			protected[field.Name] = true
+		}
+	}
+	return protected
+}
+
+// rejectProtected returns an error naming the keys in the input for protected fields, or for setters or a remain field
+// with protected paths, if there are any. It is called before any field is populated, so that the keys for a struct
+// are reported before any errors for the structs within it.
+func (d *Decoder) rejectProtected(structType reflect.Type, input reflect.Value, tagMap map[string]string,
+	protected map[string]bool, prefixes []prefixField, setters map[string]reflect.Method, paths map[int][]string,
+	remain bool) error {
+	var rejected []string
+	roots := pathRoots(paths)
+	mapRange := input.MapRange()
+	for mapRange.Next() {
+		key := mapRange.Key()
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		if key.Kind() != reflect.String {
+			continue
+		}
+		if fieldName, ok := tagMap[strings.ToLower(key.String())]; ok {
+			if protected[fieldName] {
+				rejected = append(rejected, key.String())
+			}
+		} else if i, _, ok := matchPrefix(prefixes, key.String()); ok {
+			if protected[structType.Field(prefixes[i].index).Name] {
+				rejected = append(rejected, key.String())
+			}
+		} else if _, ok := setters[strings.ToLower(key.String())]; ok {
+			if d.protectedPath(key.String()) {
+				rejected = append(rejected, key.String())
+			}
+		} else if remain && !roots[strings.ToLower(key.String())] && d.protectedPath(key.String()) {
+			rejected = append(rejected, key.String())
+		}
+	}
+	for fieldIndex, parts := range paths {
+		if _, ok := resolvePath(input, parts); ok && protected[structType.Field(fieldIndex).Name] {
+			rejected = append(rejected, strings.Join(parts, pathSeparator))
+		}
+	}
+	if len(rejected) == 0 {
+		return nil
+	}
+	sort.Strings(rejected)
+	return fmt.Errorf(protectedMsg, "'"+strings.Join(rejected, "', '")+"'", structType.Name())
+}
+
+// rejectProtectedEntries returns an error naming the keys in the input for a map with protected paths, if there are
+// any, as rejectProtected does for a struct.
+func (d *Decoder) rejectProtectedEntries(mapType reflect.Type, input reflect.Value) error {
+	if len(d.AllowFields) == 0 && len(d.DenyFields) == 0 {
+		return nil
+	}
+	var rejected []string
+	for _, key := range input.MapKeys() {
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		if keyString, _ := formatMapKey(key); d.protectedPath(keyString) {
+			rejected = append(rejected, keyString)
+		}
+	}
+	if len(rejected) == 0 {
+		return nil
+	}
+	sort.Strings(rejected)
+	return fmt.Errorf(protectedMapMsg, "'"+strings.Join(rejected, "', '")+"'", mapType.String())
+}
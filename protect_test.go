@@ -0,0 +1,257 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Profile struct {
+	Bio  string `json:"bio"`
+	Role string `json:"role"`
+}
+
+type Model struct {
+	ID      int      `json:"id,readonly"`
+	Name    string   `json:"name"`
+	IsAdmin bool     `json:"isAdmin"`
+	Profile Profile  `json:"profile"`
+	Billing Address  `prefix:"billing_"`
+	Friends []Member `json:"friends"`
+}
+
+func TestReadonlyFields(t *testing.T) {
+	var model Model
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"id": 7, "name": "Zhaoliu"}, &model)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Model{Name: "Zhaoliu"}, model, "readonly fields should be skipped")
+	}
+}
+
+func TestReadonlyFieldsOtherTag(t *testing.T) {
+	type Account struct {
+		Name    string `json:"name" form:"name"`
+		IsAdmin bool   `json:"is_admin,readonly" form:"is_admin"`
+	}
+
+	var account Account
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"name": "Zhaoliu", "is_admin": true}, &account, "form")
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Account{Name: "Zhaoliu"}, account, "fields readonly in any tag should be skipped")
+	}
+}
+
+func TestDenyFields(t *testing.T) {
+	decoder := &mapstostructs.Decoder{DenyFields: []string{"isAdmin", "Profile.Role"}}
+	input := map[string]interface{}{
+		"name":         "Zhaoliu",
+		"isadmin":      true,
+		"profile":      map[string]interface{}{"bio": "hello", "role": "owner"},
+		"billing_city": "London",
+	}
+
+	var model Model
+
+	err := decoder.MapToStruct(input, &model)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Model{
+			Name:    "Zhaoliu",
+			Profile: Profile{Bio: "hello"},
+			Billing: Address{City: "London"},
+		}, model, "denied fields should be skipped")
+	}
+}
+
+func TestAllowFields(t *testing.T) {
+	decoder := &mapstostructs.Decoder{AllowFields: []string{"name", "profile.bio"}}
+	input := map[string]interface{}{
+		"name":         "x",
+		"isadmin":      true,
+		"profile":      map[string]interface{}{"bio": "y", "role": "owner"},
+		"billing_city": "London",
+	}
+
+	var model Model
+
+	err := decoder.MapToStruct(input, &model)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Model{Name: "x", Profile: Profile{Bio: "y"}}, model, "only allowed fields should be populated")
+	}
+}
+
+func TestProtectedKeysError(t *testing.T) {
+	decoder := &mapstostructs.Decoder{AllowFields: []string{"name", "profile.bio"}, ProtectedKeys: mapstostructs.ProtectedKeysError}
+
+	var model Model
+
+	err := decoder.MapToStruct(map[string]interface{}{"name": "x", "profile": map[string]interface{}{"bio": "y"}}, &model)
+
+	if assert.Nil(t, err, "error should be nil for allowed fields") {
+		assert.Equal(t, Model{Name: "x", Profile: Profile{Bio: "y"}}, model, "allowed fields should be populated")
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"id": 7, "name": "x", "isadmin": true, "billing_city": "London"}, &model)
+
+	if assert.NotNil(t, err, "error should not be nil with protected keys") {
+		assert.Equal(t, "the key(s) 'billing_city', 'id', 'isadmin' for a struct of type Model are protected", err.Error())
+	}
+}
+
+func TestProtectedKeysErrorNested(t *testing.T) {
+	decoder := &mapstostructs.Decoder{AllowFields: []string{"name", "profile.bio"}, ProtectedKeys: mapstostructs.ProtectedKeysError}
+
+	var model Model
+
+	err := decoder.MapToStruct(map[string]interface{}{"profile": map[string]interface{}{"bio": "y", "role": "owner"}}, &model)
+
+	if assert.NotNil(t, err, "error should not be nil with protected nested keys") {
+		assert.Equal(t, "the Profile field for a struct of type Model the key(s) 'role' for a struct of type Profile are protected", err.Error())
+	}
+}
+
+func TestProtectedKeysErrorSetters(t *testing.T) {
+	decoder := &mapstostructs.Decoder{DenyFields: []string{"friends.name"}, Setters: true, ProtectedKeys: mapstostructs.ProtectedKeysError}
+	rows := []map[string]interface{}{{"friends": []interface{}{map[string]interface{}{"age": 1}, map[string]interface{}{"name": "x"}}}}
+
+	var models []Model
+
+	err := decoder.MapsToStructs(rows, &models)
+
+	if assert.NotNil(t, err, "error should not be nil with protected keys for setters") {
+		assert.Equal(t, "the Friends field for a struct of type Model the key(s) 'name' for a struct of type Member are protected in row 2 in row 1", err.Error())
+	}
+}
+
+func TestReadonlyFieldsTuples(t *testing.T) {
+	var model Model
+
+	err := mapstostructs.Convert([]interface{}{7, "Zhaoliu", true}, &model)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Model{Name: "Zhaoliu", IsAdmin: true}, model, "readonly positions should be skipped")
+	}
+}
+
+func TestDenyFieldsTuples(t *testing.T) {
+	decoder := &mapstostructs.Decoder{DenyFields: []string{"isAdmin", "profile.role"}}
+
+	var model Model
+
+	err := decoder.MapToStruct(map[string]interface{}{"name": "Zhaoliu", "profile": []interface{}{"hello", "owner"}}, &model)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Model{Name: "Zhaoliu", Profile: Profile{Bio: "hello"}}, model, "denied nested positions should be skipped")
+	}
+}
+
+func TestAllowFieldsTuples(t *testing.T) {
+	decoder := &mapstostructs.Decoder{AllowFields: []string{"name"}}
+
+	var model Model
+
+	err := decoder.Convert([]interface{}{7, "Zhaoliu", true}, &model)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Model{Name: "Zhaoliu"}, model, "positions outside the allowed fields should be skipped")
+	}
+}
+
+func TestProtectedKeysErrorTuples(t *testing.T) {
+	decoder := &mapstostructs.Decoder{DenyFields: []string{"isAdmin"}, ProtectedKeys: mapstostructs.ProtectedKeysError}
+
+	var model Model
+
+	err := decoder.Convert([]interface{}{7, "Zhaoliu", true}, &model)
+
+	if assert.NotNil(t, err, "error should not be nil for protected positions") {
+		assert.Equal(t, "the key(s) 'id', 'isAdmin' for a struct of type Model are protected", err.Error())
+	}
+
+	err = decoder.Convert([]interface{}{nil, "Zhaoliu"}, &model)
+
+	if assert.NotNil(t, err, "error should not be nil for a protected position with a nil value") {
+		assert.Equal(t, "the key(s) 'id' for a struct of type Model are protected", err.Error())
+	}
+}
+
+type Listing struct {
+	Name  string                 `json:"name"`
+	Meta  map[string]interface{} `json:"meta"`
+	Extra map[string]interface{} `json:",remain"`
+}
+
+func TestAllowFieldsMaps(t *testing.T) {
+	decoder := &mapstostructs.Decoder{AllowFields: []string{"name", "meta.public"}}
+	input := map[string]interface{}{
+		"name": "x",
+		"meta": map[string]interface{}{"public": 1, "isAdmin": true},
+		"zzz":  1,
+	}
+
+	var listing Listing
+
+	err := decoder.MapToStruct(input, &listing)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Listing{Name: "x", Meta: map[string]interface{}{"public": 1}}, listing, "map keys and remain keys outside the allowed paths should be skipped")
+	}
+}
+
+func TestDenyFieldsMaps(t *testing.T) {
+	decoder := &mapstostructs.Decoder{DenyFields: []string{"meta.isadmin", "meta.inner.secret", "zzz"}}
+	input := map[string]interface{}{
+		"name": "x",
+		"meta": map[string]interface{}{"public": 1, "isAdmin": true, "inner": map[string]interface{}{"secret": 1, "ok": 2}},
+		"zzz":  1,
+		"yyy":  2,
+	}
+
+	var listing Listing
+
+	err := decoder.MapToStruct(input, &listing)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Listing{Name: "x", Meta: map[string]interface{}{"public": 1, "inner": map[string]interface{}{"ok": 2}}, Extra: map[string]interface{}{"yyy": 2}}, listing, "denied map keys and remain keys should be skipped")
+	}
+}
+
+func TestProtectedKeysErrorMaps(t *testing.T) {
+	decoder := &mapstostructs.Decoder{AllowFields: []string{"name", "meta.public"}, ProtectedKeys: mapstostructs.ProtectedKeysError}
+
+	var listing Listing
+
+	err := decoder.MapToStruct(map[string]interface{}{"name": "x", "meta": map[string]interface{}{"public": 1, "isAdmin": true}}, &listing)
+
+	if assert.NotNil(t, err, "error should not be nil with protected map keys") {
+		assert.Equal(t, "the Meta field for a struct of type Listing the key(s) 'isAdmin' for a map[string]interface {} are protected", err.Error())
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"name": "x", "zzz": 1}, &listing)
+
+	if assert.NotNil(t, err, "error should not be nil with protected remain keys") {
+		assert.Equal(t, "the key(s) 'zzz' for a struct of type Listing are protected", err.Error())
+	}
+}
+
+func TestReadonlyRemain(t *testing.T) {
+	type Labels struct {
+		Name  string            `json:"name"`
+		Other map[string]string `json:",remain,readonly"`
+	}
+
+	var labels Labels
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"name": "x", "colour": "blue"}, &labels)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Labels{Name: "x"}, labels, "a readonly remain field should not be populated")
+	}
+}
@@ -0,0 +1,36 @@
+package mapstostructs
+
+import "reflect"
+
+// RawValue holds a value from the input untouched, so that how to convert it can be decided later, for example from
+// the values of sibling fields, in the same way as json.RawMessage. A field of type RawValue or *RawValue stores the
+// value it is given, and Decode converts it with the settings of the Decoder which stored it. A missing or nil value
+// leaves the field unset.
+type RawValue struct {
+	// Value is the value from the input, such as a map[string]interface{}.
+	Value interface{}
+
+	decoder *Decoder
+}
+
+var rawValueType = reflect.TypeOf(RawValue{})
+
+// Decode converts the value into the receiver, which must be a pointer, as Convert would with the settings of the
+// Decoder which stored the value. A Decoder may be given to use other settings instead.
+func (r RawValue) Decode(receiver interface{}, decoder ...*Decoder) error {
+	d := r.decoder
+	if len(decoder) > 0 {
+		d = decoder[0]
+	}
+	if d == nil {
+		d = NewDecoder()
+	}
+	return d.Convert(r.Value, receiver)
+}
+
+// rawValue stores the input in a RawValue with a copy of the Decoder's settings, so that later changes to the Decoder
+// do not affect it.
+func (d *Decoder) rawValue(input reflect.Value) reflect.Value {
+	settings := *d
+	return reflect.ValueOf(RawValue{Value: input.Interface(), decoder: &settings})
+}
@@ -0,0 +1,103 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Shape struct {
+	Kind   string                  `json:"kind"`
+	Params mapstostructs.RawValue  `json:"params"`
+	Extra  *mapstostructs.RawValue `json:"extra"`
+}
+
+type Circle struct {
+	Radius float64 `json:"r"`
+}
+
+type Square struct {
+	Side int `json:"s"`
+}
+
+func TestRawValue(t *testing.T) {
+	input := map[string]interface{}{
+		"kind":   "circle",
+		"params": map[string]interface{}{"r": 1.5},
+	}
+
+	var shape Shape
+
+	err := mapstostructs.MapToStruct(input, &shape)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, "circle", shape.Kind)
+		assert.Equal(t, map[string]interface{}{"r": 1.5}, shape.Params.Value, "the raw value should be stored untouched")
+		assert.Nil(t, shape.Extra, "a missing raw value should be left unset")
+
+		var circle Circle
+
+		err = shape.Params.Decode(&circle)
+
+		if assert.Nil(t, err, "error should be nil for valid call") {
+			assert.Equal(t, Circle{Radius: 1.5}, circle, "the raw value should be decoded later")
+		}
+	}
+}
+
+func TestRawValueDecodeWithDecoder(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Exact: true}
+
+	var shape Shape
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"params": map[string]interface{}{"s": 2.0}}, &shape)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		var square Square
+
+		err = shape.Params.Decode(&square)
+
+		if assert.Nil(t, err, "error should be nil with the settings of the package-level function") {
+			assert.Equal(t, Square{Side: 2}, square)
+		}
+
+		err = shape.Params.Decode(&square, decoder)
+
+		if assert.NotNil(t, err, "error should not be nil with the settings of the given Decoder") {
+			assert.Equal(t, "the Side field for a struct of type Square must be assignable to int type without conversion, but received float64 type '2'", err.Error())
+		}
+	}
+}
+
+func TestRawValueKeepsSettings(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Exact: true}
+
+	var shape Shape
+
+	err := decoder.MapToStruct(map[string]interface{}{"params": map[string]interface{}{"s": 2.0}}, &shape)
+	decoder.Exact = false
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		var square Square
+
+		err = shape.Params.Decode(&square)
+
+		assert.NotNil(t, err, "error should not be nil with the settings of the Decoder when the value was stored")
+	}
+}
+
+func TestRawValueStructToMap(t *testing.T) {
+	var shape Shape
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"params": map[string]interface{}{"s": 2.0}}, &shape)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		output, err := mapstostructs.StructToMap(shape)
+
+		if assert.Nil(t, err, "error should be nil for valid call") {
+			assert.Equal(t, map[string]interface{}{"kind": "", "params": map[string]interface{}{"s": 2.0}, "extra": nil}, output, "raw values should be output as their values")
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	remainOption = "remain"
+	badRemainMsg = "the remain field %s for a struct of type %s must be a map with string keys, but is a %s"
+)
+
+// remainField returns the index of the first field of a struct type with the remain option in its tag, or -1.
+func remainField(structType reflect.Type, tags []string) (int, error) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !hasRemainOption(field, tags) {
+			continue
+		}
+		if field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String {
+			return -1, fmt.Errorf(badRemainMsg, field.Name, structType.Name(), field.Type.String())
+		}
+		return i, nil
+	}
+	return -1, nil
+}
+
+func hasRemainOption(field reflect.StructField, tags []string) bool {
+	return hasTagOption(field, tags, remainOption)
+}
+
+// pathRoots returns the lowercased first parts of the paths of a struct type's path fields, which are not unmatched
+// keys.
+func pathRoots(paths map[int][]string) map[string]bool {
+	if len(paths) == 0 {
+		return nil
+	}
+	roots := make(map[string]bool, len(paths))
+	for _, parts := range paths {
+		roots[strings.ToLower(parts[0])] = true
+	}
+	return roots
+}
@@ -0,0 +1,129 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Product struct {
+	ID      int                    `json:"id"`
+	Name    string                 `json:"name"`
+	Billing Address                `prefix:"billing_"`
+	Extra   map[string]interface{} `json:",remain"`
+}
+
+type Labels struct {
+	Name  string            `json:"name"`
+	Other map[string]string `json:"other,remain"`
+}
+
+type BadLabels struct {
+	Other []string `json:",remain"`
+}
+
+func TestRemainTag(t *testing.T) {
+	input := map[string]interface{}{
+		"id":           1,
+		"NAME":         "Widget",
+		"billing_city": "London",
+		"colour":       "blue",
+		"x-rating":     5,
+	}
+
+	var product Product
+
+	err := mapstostructs.MapToStruct(input, &product)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Product{
+			ID:      1,
+			Name:    "Widget",
+			Billing: Address{City: "London"},
+			Extra:   map[string]interface{}{"colour": "blue", "x-rating": 5},
+		}, product, "unmatched keys should be collected in the remain field")
+	}
+}
+
+func TestRemainTagStructToMap(t *testing.T) {
+	product := Product{
+		ID:      1,
+		Name:    "Widget",
+		Billing: Address{City: "London"},
+		Extra:   map[string]interface{}{"colour": "blue", "x-rating": 5},
+	}
+
+	output, err := mapstostructs.StructToMap(product)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{
+			"id":              1,
+			"name":            "Widget",
+			"billing_city":    "London",
+			"billing_country": "",
+			"colour":          "blue",
+			"x-rating":        5,
+		}, output, "the remain field should be merged into the output")
+	}
+}
+
+func TestRemainTagEmpty(t *testing.T) {
+	decoder := &mapstostructs.Decoder{ErrorUnmapped: true}
+
+	var product Product
+
+	err := decoder.MapToStruct(map[string]interface{}{"id": 1, "name": "x", "billing_city": "y", "billing_country": "z"}, &product)
+
+	if assert.Nil(t, err, "error should be nil without unmatched keys") {
+		assert.Nil(t, product.Extra, "the remain field should be nil without unmatched keys")
+	}
+}
+
+func TestRemainTagConvert(t *testing.T) {
+	var labels Labels
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"name": "x", "colour": "blue"}, &labels)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Labels{Name: "x", Other: map[string]string{"colour": "blue"}}, labels, "remain values should be converted")
+	}
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"name": "x", "size": 2.5}, &labels)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the Other field for a struct of type Labels the map value for a map[string]string must be or be convertible to string type, but received '2.5'", err.Error())
+	}
+}
+
+func TestRemainTagConflict(t *testing.T) {
+	_, err := mapstostructs.StructToMap(Labels{Name: "x", Other: map[string]string{"name": "y"}})
+
+	if assert.NotNil(t, err, "error should not be nil with conflicting keys") {
+		assert.Equal(t, "the key 'name' for the Other field for a struct of type Labels conflicts with another value", err.Error())
+	}
+}
+
+func TestRemainTagBadField(t *testing.T) {
+	var bad BadLabels
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"a": 1}, &bad)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid remain field") {
+		assert.Equal(t, "the remain field Other for a struct of type BadLabels must be a map with string keys, but is a []string", err.Error())
+	}
+}
+
+func TestRemainColumns(t *testing.T) {
+	columns, err := mapstostructs.StructsToColumns([]Product{
+		{ID: 1, Extra: map[string]interface{}{"colour": "blue"}},
+		{ID: 2},
+	})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.NotContains(t, columns, "Extra", "the remain field should not have a column")
+		assert.Equal(t, []interface{}{"blue", nil}, columns["colour"], "remain keys should have columns")
+		assert.Equal(t, []interface{}{1, 2}, columns["id"], "other fields should have columns")
+	}
+}
@@ -0,0 +1,206 @@
+package mapstostructs
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+func (d *Decoder) structToMap(input reflect.Value) (map[string]interface{}, error) {
+	structType := input.Type()
+	numFields := structType.NumField()
+	output := make(map[string]interface{}, numFields)
+	info := d.structInfo(structType)
+	paths := info.paths
+	prefixes := info.prefixes
+	remainIndex := info.remainIndex
+	if info.remainErr != nil {
+		return nil, info.remainErr
+	}
+	for i := 0; i < numFields; i++ {
+		field := structType.Field(i)
+		if _, ok := paths[i]; ok || i == remainIndex || field.PkgPath != "" || field.Tag.Get(prefixTag) != "" ||
+			omitted(field, d.Tags) || !d.inGroups(field) {
+			continue
+		}
+		value, err := d.toInterface(input.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf(structPrefix+"%s", field.Name, structType.Name(), err)
+		}
+		output[fieldKey(field, d.Tags)] = value
+	}
+	for _, prefix := range prefixes {
+		field := structType.Field(prefix.index)
+		if !d.inGroups(field) {
+			continue
+		}
+		value, err := d.toInterface(input.Field(prefix.index))
+		if err != nil {
+			return nil, fmt.Errorf(structPrefix+"%s", field.Name, structType.Name(), err)
+		}
+		switch value := value.(type) {
+		case nil:
+		case map[string]interface{}:
+			if err := insertPrefixed(output, prefix, value, structType); err != nil {
+				return nil, err
+			}
+		default:
+			output[fieldKey(field, d.Tags)] = value
+		}
+	}
+	for i := 0; i < numFields; i++ {
+		parts, ok := paths[i]
+		if !ok || !d.inGroups(structType.Field(i)) {
+			continue
+		}
+		value, err := d.toInterface(input.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf(structPrefix+"%s", structType.Field(i).Name, structType.Name(), err)
+		}
+		if !insertPath(output, parts, value) {
+			return nil, pathConflictError(structType, i, parts)
+		}
+	}
+	if remainIndex >= 0 && d.inGroups(structType.Field(remainIndex)) {
+		value, err := d.toInterface(input.Field(remainIndex))
+		if err != nil {
+			return nil, fmt.Errorf(structPrefix+"%s", structType.Field(remainIndex).Name, structType.Name(), err)
+		}
+		if remain, ok := value.(map[string]interface{}); ok {
+			if err := insertPrefixed(output, prefixField{index: remainIndex}, remain, structType); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if d.Setters {
+		if err := d.addGetters(input, output); err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
+
+// toInterface converts a value into the form produced by JSON-unmarshalling into an interface{}: structs become
+// map[string]interface{}, slices and arrays become []interface{} and maps become map[string]interface{}. Registered
+// enum values become their names, and values implementing MapMarshaler the result of MarshalMap. Other values are
+// returned unchanged.
+func (d *Decoder) toInterface(input reflect.Value) (interface{}, error) {
+	if !input.IsValid() {
+		return nil, nil
+	}
+	if name, ok := enumName(input); ok {
+		return name, nil
+	}
+	if input.Kind() != reflect.Ptr && input.Kind() != reflect.Interface {
+		if output, handled, err := marshalMap(input); handled {
+			if err != nil || output == nil || reflect.TypeOf(output) == input.Type() {
+				return output, err
+			}
+			return d.toInterface(reflect.ValueOf(output))
+		}
+	}
+
+	switch input.Kind() {
+
+	case reflect.Ptr, reflect.Interface:
+		if input.IsNil() {
+			return nil, nil
+		}
+		return d.toInterface(input.Elem())
+
+	case reflect.Struct:
+		if input.Type() == rawValueType {
+			return d.toInterface(input.FieldByName("Value"))
+		}
+		if !hasExportedFields(input.Type()) && !(d.Setters && len(getterMethods(input.Type())) > 0) {
+			return input.Interface(), nil
+		}
+		return d.structToMap(input)
+
+	case reflect.Slice, reflect.Array:
+		if input.Kind() == reflect.Slice && input.IsNil() {
+			return nil, nil
+		}
+		if d.SlicesToIndexMaps {
+			return d.indexMapInterface(input)
+		}
+		output := make([]interface{}, input.Len())
+		for i := 0; i < input.Len(); i++ {
+			value, err := d.toInterface(input.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("%s"+rowSuffix, err, i+1)
+			}
+			output[i] = value
+		}
+		return output, nil
+
+	case reflect.Map:
+		if input.IsNil() {
+			return nil, nil
+		}
+		output := make(map[string]interface{}, input.Len())
+		mapRange := input.MapRange()
+		for mapRange.Next() {
+			key, ok := formatMapKey(mapRange.Key())
+			if !ok {
+				return nil, fmt.Errorf(mapKeyPrefix+badValueMsg, input.Type().String(), "string", mapRange.Key().Interface())
+			}
+			value, err := d.toInterface(mapRange.Value())
+			if err != nil {
+				return nil, fmt.Errorf(mapValuePrefix+"%s", input.Type().String(), err)
+			}
+			output[key] = value
+		}
+		return output, nil
+	}
+
+	return input.Interface(), nil
+}
+
+// formatMapKey is the reverse of the string parsing performed by convertToType for map keys, using the
+// encoding.TextMarshaler implementation of a key where there is one.
+func formatMapKey(key reflect.Value) (string, bool) {
+	if key.Kind() == reflect.Interface {
+		key = key.Elem()
+	}
+	if !key.IsValid() {
+		return "", false
+	}
+	if name, ok := enumName(key); ok {
+		return name, true
+	}
+	if key.Type().Implements(textMarshalerType) {
+		text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err == nil
+	}
+
+	switch key.Kind() {
+
+	case reflect.String:
+		return key.String(), true
+
+	case reflect.Bool:
+		return strconv.FormatBool(key.Bool()), true
+
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		return strconv.FormatInt(key.Int(), 10), true
+
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return strconv.FormatUint(key.Uint(), 10), true
+
+	case reflect.Float64, reflect.Float32:
+		return strconv.FormatFloat(key.Float(), 'g', -1, key.Type().Bits()), true
+	}
+
+	return "", false
+}
+
+func hasExportedFields(structType reflect.Type) bool {
+	for i := 0; i < structType.NumField(); i++ {
+		if structType.Field(i).PkgPath == "" {
+			return true
+		}
+	}
+	return false
+}
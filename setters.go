@@ -0,0 +1,117 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	setterPrefix  = "the %s method for a struct of type %s "
+	setterFailMsg = "the %s method for a struct of type %s failed: %s"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// setterCall is a setter method to call with a value from the input.
+type setterCall struct {
+	method reflect.Method
+	input  reflect.Value
+}
+
+// setterMethods returns the methods of a struct type, or of its pointer type, named SetX with a single parameter which
+// is not variadic and returning nothing or an error, keyed by X in lower case.
+func setterMethods(structType reflect.Type) map[string]reflect.Method {
+	ptrType := reflect.PtrTo(structType)
+	setters := make(map[string]reflect.Method)
+	for i := 0; i < ptrType.NumMethod(); i++ {
+		method := ptrType.Method(i)
+		if len(method.Name) > 3 && strings.HasPrefix(method.Name, "Set") && method.Type.NumIn() == 2 &&
+			!method.Type.IsVariadic() &&
+			(method.Type.NumOut() == 0 || method.Type.NumOut() == 1 && method.Type.Out(0) == errorType) {
+			setters[strings.ToLower(method.Name[3:])] = method
+		}
+	}
+	return setters
+}
+
+// getterMethods returns the methods of a struct type, or of its pointer type, named GetX, or X where there is a
+// setter named SetX, with no parameters and returning a value or a value and an error, keyed by X.
+func getterMethods(structType reflect.Type) map[string]reflect.Method {
+	ptrType := reflect.PtrTo(structType)
+	setters := setterMethods(structType)
+	getters := make(map[string]reflect.Method)
+	for i := 0; i < ptrType.NumMethod(); i++ {
+		method := ptrType.Method(i)
+		if method.Type.NumIn() != 1 ||
+			!(method.Type.NumOut() == 1 || method.Type.NumOut() == 2 && method.Type.Out(1) == errorType) {
+			continue
+		}
+		if len(method.Name) > 3 && strings.HasPrefix(method.Name, "Get") {
+			getters[method.Name[3:]] = method
+		} else if _, ok := setters[strings.ToLower(method.Name)]; ok {
+			if _, ok := getters[method.Name]; !ok {
+				getters[method.Name] = method
+			}
+		}
+	}
+	return getters
+}
+
+// callSetters calls the setter methods of a struct in the order of their names, converting each input into the type
+// of the setter's parameter.
+func (d *Decoder) callSetters(structValue reflect.Value, calls []setterCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	structType := structValue.Type()
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].method.Index < calls[j].method.Index
+	})
+	for _, call := range calls {
+		argument := reflect.New(call.method.Type.In(1)).Elem()
+		if err := d.setRecursively(argument, call.input); err != nil {
+			return fmt.Errorf(setterPrefix+"%s", call.method.Name, structType.Name(), err)
+		}
+		results := structValue.Addr().Method(call.method.Index).Call([]reflect.Value{argument})
+		if len(results) > 0 && !results[0].IsNil() {
+			return fmt.Errorf(setterFailMsg, call.method.Name, structType.Name(), results[0].Interface().(error).Error())
+		}
+	}
+	return nil
+}
+
+// addGetters adds the values returned by the getter methods of a struct to the output, for keys not already present
+// in any case.
+func (d *Decoder) addGetters(input reflect.Value, output map[string]interface{}) error {
+	structType := input.Type()
+	getters := getterMethods(structType)
+	if len(getters) == 0 {
+		return nil
+	}
+	present := make(map[string]bool, len(output))
+	for key := range output {
+		present[strings.ToLower(key)] = true
+	}
+	if !input.CanAddr() {
+		copied := reflect.New(structType).Elem()
+		copied.Set(input)
+		input = copied
+	}
+	for key, method := range getters {
+		if present[strings.ToLower(key)] {
+			continue
+		}
+		results := input.Addr().Method(method.Index).Call(nil)
+		if len(results) > 1 && !results[1].IsNil() {
+			return fmt.Errorf(setterFailMsg, method.Name, structType.Name(), results[1].Interface().(error).Error())
+		}
+		value, err := d.toInterface(results[0])
+		if err != nil {
+			return fmt.Errorf(setterPrefix+"%s", method.Name, structType.Name(), err)
+		}
+		output[key] = value
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package mapstostructs_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Member struct {
+	ID    int `json:"id"`
+	name  string
+	age   int
+	email string
+}
+
+func (m *Member) SetName(name string) {
+	m.name = name
+}
+
+func (m *Member) Name() string {
+	return m.name
+}
+
+func (m *Member) SetAge(age int) error {
+	if age < 0 {
+		return fmt.Errorf("%d is negative", age)
+	}
+	m.age = age
+	return nil
+}
+
+func (m Member) GetAge() int {
+	return m.age
+}
+
+func (m *Member) SetEmail(email string) {
+	m.email = email
+}
+
+func (m Member) Summary() string {
+	return fmt.Sprintf("%s (%d)", m.name, m.age)
+}
+
+type Club struct {
+	Members []Member `json:"members"`
+}
+
+func TestSetters(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Setters: true}
+
+	var member Member
+
+	err := decoder.MapToStruct(map[string]interface{}{"id": 1, "NAME": "Zhaoliu", "age": 30.0}, &member)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Member{ID: 1, name: "Zhaoliu", age: 30}, member, "setters should be called with converted values")
+	}
+}
+
+func TestGetters(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Setters: true}
+
+	output, err := decoder.StructToMap(Member{ID: 1, name: "Zhaoliu", age: 30})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, map[string]interface{}{"id": 1, "Name": "Zhaoliu", "Age": 30}, output, "getters should be called")
+	}
+}
+
+func TestSettersNested(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Setters: true}
+
+	var club Club
+
+	err := decoder.MapToStruct(map[string]interface{}{"members": []interface{}{map[string]interface{}{"id": 1, "name": "Zhaoliu"}}}, &club)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []Member{{ID: 1, name: "Zhaoliu"}}, club.Members, "setters should be called at any depth")
+	}
+}
+
+func TestSettersDefault(t *testing.T) {
+	var member Member
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"id": 1, "name": "Zhaoliu", "age": 30}, &member)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Member{ID: 1}, member, "setters should not be called by default")
+	}
+}
+
+func TestSettersErrors(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Setters: true}
+
+	var member Member
+
+	err := decoder.MapToStruct(map[string]interface{}{"age": -1}, &member)
+
+	if assert.NotNil(t, err, "error should not be nil with a failing setter") {
+		assert.Equal(t, "the SetAge method for a struct of type Member failed: -1 is negative", err.Error())
+	}
+
+	err = decoder.MapToStruct(map[string]interface{}{"age": "old"}, &member)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the SetAge method for a struct of type Member must be or be convertible to int type, but received 'old'", err.Error())
+	}
+}
+
+type Tally struct {
+	ID     int `json:"id"`
+	values []int
+}
+
+func (t *Tally) SetValues(values ...int) {
+	t.values = values
+}
+
+func TestSettersVariadic(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Setters: true}
+
+	var tally Tally
+
+	err := decoder.MapToStruct(map[string]interface{}{"id": 1, "values": []int{1, 2}}, &tally)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Tally{ID: 1}, tally, "variadic methods should not be used as setters")
+	}
+}
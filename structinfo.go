@@ -0,0 +1,61 @@
+package mapstostructs
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structInfo is what the tags and methods of a struct type say about how to populate it, so that they are read once
+// per type rather than once per struct populated. It must not be modified once cached.
+type structInfo struct {
+	tagMap      map[string]string
+	prefixes    []prefixField
+	paths       map[int][]string
+	remainIndex int
+	remainErr   error
+	readonly    map[string]bool
+	validated   bool
+	hasBefore   bool
+	hasAfter    bool
+}
+
+type structInfoKey struct {
+	structType reflect.Type
+	tags       string
+}
+
+var structInfoCache sync.Map
+
+// structInfo returns the structInfo for a struct type with the Tags setting, reading it when the type is first used.
+func (d *Decoder) structInfo(structType reflect.Type) *structInfo {
+	key := structInfoKey{structType: structType, tags: strings.Join(d.Tags, ",")}
+	if info, ok := structInfoCache.Load(key); ok {
+		return info.(*structInfo)
+	}
+	info := &structInfo{
+		tagMap:    makeTagMap(structType, d.Tags),
+		prefixes:  prefixFields(structType),
+		paths:     pathFields(structType),
+		hasBefore: reflect.PtrTo(structType).Implements(beforeMapDecoderType),
+		hasAfter:  reflect.PtrTo(structType).Implements(afterMapDecoderType),
+	}
+	info.remainIndex, info.remainErr = remainField(structType, d.Tags)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if isReadonly(field, d.Tags) {
+			if info.readonly == nil {
+				info.readonly = make(map[string]bool)
+			}
+			info.readonly[field.Name] = true
+		}
+		if _, ok := field.Tag.Lookup(validateTag); ok {
+			info.validated = true
+		}
+	}
+	structInfoCache.Store(key, info)
+	return info
+}
@@ -0,0 +1,159 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type UserDTO struct {
+	ID       float64                `json:"id"`
+	FullName string                 `json:"name"`
+	Sex      string                 `json:"gender"`
+	Sports   []string               `json:"sports"`
+	Location map[string]interface{} `json:"location"`
+}
+
+type UserDomain struct {
+	ID       int       `domain:"id"`
+	Name     string    `domain:"name"`
+	Gender   string    `domain:"gender"`
+	Sports   []string  `domain:"sports"`
+	Location *Location `domain:"location"`
+	Email    string    `domain:"email"`
+}
+
+func TestStructToStruct(t *testing.T) {
+	dto := UserDTO{
+		ID:       213,
+		FullName: "Zhaoliu",
+		Sex:      "male",
+		Sports:   []string{"football"},
+		Location: map[string]interface{}{"city": "London"},
+	}
+
+	var domain UserDomain
+
+	err := mapstostructs.StructToStruct(&dto, &domain, "domain")
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, 213, domain.ID, "values should be converted")
+		assert.Equal(t, "Zhaoliu", domain.Name, "fields should be matched by tags")
+		assert.Equal(t, []string{"football"}, domain.Sports, "slices should be copied")
+		if assert.NotNil(t, domain.Location, "nested types should be converted") {
+			assert.Equal(t, "London", domain.Location.City, "nested types should be converted")
+		}
+	}
+
+	var back UserDTO
+
+	err = mapstostructs.StructToStruct(domain, &back, "domain")
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, dto.ID, back.ID, "values should be converted back")
+		assert.Equal(t, map[string]interface{}{"country": "", "city": "London"}, back.Location, "nested structs should be converted to maps")
+	}
+}
+
+func TestStructToStructUnmapped(t *testing.T) {
+	decoder := &mapstostructs.Decoder{Tags: []string{"domain"}, ErrorUnmapped: true}
+
+	var domain UserDomain
+
+	err := decoder.StructToStruct(UserDTO{ID: 1}, &domain)
+
+	if assert.NotNil(t, err, "error should not be nil with unmapped fields") {
+		assert.Equal(t, "the Email field(s) for a struct of type UserDomain had no input to map from", err.Error())
+	}
+
+	var users []User
+
+	err = decoder.MapsToStructs([]map[string]interface{}{{"id": 1, "name": "Lisi", "gender": "female", "age": 54, "sports": nil}}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with missing keys") {
+		assert.Equal(t, "the Location field(s) for a struct of type User had no input to map from in row 1", err.Error())
+	}
+
+	err = decoder.StructToStruct(UserDomain{}, &UserDTO{})
+
+	assert.Nil(t, err, "error should be nil when all fields are mapped")
+}
+
+func TestStructsToStructs(t *testing.T) {
+	dtos := []*UserDTO{{ID: 213, FullName: "Zhaoliu"}, nil, {ID: 56, FullName: "Zhangsan"}}
+
+	var domains []UserDomain
+
+	err := mapstostructs.StructsToStructs(dtos, &domains, "domain")
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		if assert.Equal(t, 3, len(domains), "all rows should be returned") {
+			assert.Equal(t, 213, domains[0].ID, "values should be correctly set")
+			assert.Equal(t, UserDomain{}, domains[1], "nil rows should be left as zero values")
+			assert.Equal(t, "Zhangsan", domains[2].Name, "values should be correctly set")
+		}
+	}
+
+	err = mapstostructs.StructsToStructs([]struct{ ID string }{{ID: "x"}}, &domains, "domain")
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the ID field for a struct of type UserDomain must be or be convertible to int type, but received 'x' in row 1", err.Error())
+	}
+
+	err = mapstostructs.StructsToStructs([]int{1}, &domains)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid input") {
+		assert.Equal(t, "the input argument must be a slice or an array of struct but a slice of int was given", err.Error())
+	}
+
+	err = mapstostructs.StructToStruct(1, &domains)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid input") {
+		assert.Equal(t, "the input argument must be a struct or a ptr to a struct but a int was given", err.Error())
+	}
+}
+
+type LocationV1 struct {
+	Nation string `json:"country"`
+	Town   string `json:"city"`
+	Zip    int    `json:"zip"`
+}
+
+type Addresses struct {
+	Home     Location               `json:"home"`
+	Work     *Location              `json:"work"`
+	Previous []Location             `json:"previous"`
+	Raw      map[string]interface{} `json:"raw"`
+	Strings  map[string]string      `json:"strings"`
+}
+
+func TestNestedStructToStruct(t *testing.T) {
+	v1 := LocationV1{Nation: "UK", Town: "London", Zip: 1}
+	input := map[string]interface{}{
+		"home":     v1,
+		"work":     &v1,
+		"previous": []LocationV1{v1},
+		"raw":      v1,
+		"strings":  Location{Country: "FR", City: "Paris"},
+	}
+
+	var addresses Addresses
+
+	err := mapstostructs.MapToStruct(input, &addresses)
+
+	if assert.Nil(t, err, "error should be nil for nested structs of another type") {
+		assert.Equal(t, Location{Country: "UK", City: "London"}, addresses.Home, "struct fields should be matched by tag")
+		assert.Equal(t, Location{Country: "UK", City: "London"}, *addresses.Work, "pointers to structs should be matched by tag")
+		assert.Equal(t, []Location{{Country: "UK", City: "London"}}, addresses.Previous, "slices of structs should be matched by tag")
+		assert.Equal(t, map[string]interface{}{"country": "UK", "city": "London", "zip": 1}, addresses.Raw, "structs should populate maps")
+		assert.Equal(t, map[string]string{"country": "FR", "city": "Paris"}, addresses.Strings, "structs should populate typed maps")
+	}
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"strings": v1}, &addresses)
+
+	if assert.NotNil(t, err, "error should not be nil for unconvertible struct fields") {
+		assert.Equal(t, "the Strings field for a struct of type Addresses the map value for a map[string]string must be or be convertible to string type, but received '1'", err.Error())
+	}
+}
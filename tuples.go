@@ -0,0 +1,162 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	posTag    = "pos"
+	badPosMsg = "the pos tag for the %s field for a struct of type %s must be a non-negative integer, but received '%s'"
+	dupPosMsg = "the pos tag for the %s field for a struct of type %s duplicates the position %d"
+)
+
+// tuplePositions returns the index of the struct field for each position in a tuple, or -1 where there is none. If
+// any field has a pos tag, only the fields with pos tags have positions, otherwise the exported fields are in order.
+func tuplePositions(structType reflect.Type) ([]int, error) {
+	var (
+		positions []int
+		tagged    bool
+	)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag, ok := field.Tag.Lookup(posTag)
+		if !ok || field.PkgPath != "" {
+			continue
+		}
+		tagged = true
+		position, err := strconv.Atoi(tag)
+		if err != nil || position < 0 {
+			return nil, fmt.Errorf(badPosMsg, field.Name, structType.Name(), tag)
+		}
+		for len(positions) <= position {
+			positions = append(positions, -1)
+		}
+		if positions[position] >= 0 {
+			return nil, fmt.Errorf(dupPosMsg, field.Name, structType.Name(), position)
+		}
+		positions[position] = i
+	}
+	if tagged {
+		return positions, nil
+	}
+	for i := 0; i < structType.NumField(); i++ {
+		if structType.Field(i).PkgPath == "" {
+			positions = append(positions, i)
+		}
+	}
+	return positions, nil
+}
+
+// setStructFromTuple populates a struct from a slice or an array by position, such as [213, "Zhaoliu", "male"].
+// Values beyond the last position are ignored. Fields without a value in the tuple are unmapped, and are given their
+// defaults or reported by ErrorUnmapped as with a map.
+func (d *Decoder) setStructFromTuple(receiver reflect.Value, input reflect.Value) error {
+	wantType := receiver.Type()
+	if receiver.Kind() == reflect.Ptr {
+		wantType = wantType.Elem()
+	}
+	positions, err := tuplePositions(wantType)
+	if err != nil {
+		return err
+	}
+	defaults, err := d.defaults(wantType)
+	if err != nil {
+		return err
+	}
+	var mapped, nulls map[string]bool
+	if d.ErrorUnmapped || defaults != nil {
+		mapped = make(map[string]bool, len(positions))
+		nulls = make(map[string]bool)
+	}
+	info := d.structInfo(wantType)
+	inactive := d.inactiveFields(wantType)
+	protected := d.protectedFields(wantType, info.readonly, inactive)
+	if d.ProtectedKeys == ProtectedKeysError {
+		if err := d.rejectProtectedPositions(wantType, input, positions, protected); err != nil {
+			return err
+		}
+	}
+	newStructValue := reflect.New(wantType).Elem()
+	for i := 0; i < input.Len() && i < len(positions); i++ {
+		if positions[i] < 0 {
+			continue
+		}
+		field := wantType.Field(positions[i])
+		if protected[field.Name] || inactive[field.Name] {
+			continue
+		}
+		receivingField := newStructValue.Field(positions[i])
+		if err := d.at(fieldKey(field, d.Tags)).setRecursively(receivingField, input.Index(i)); err != nil {
+			return fmt.Errorf(structPrefix+"%s", field.Name, wantType.Name(), err)
+		}
+		if mapped != nil {
+			mapped[field.Name] = true
+			nulls[field.Name] = d.DefaultOnNil && isNil(input.Index(i))
+		}
+	}
+	if mapped != nil {
+		for fieldName := range inactive {
+			mapped[fieldName], nulls[fieldName] = true, false
+		}
+	}
+	setDefaults(newStructValue, defaults, mapped, nulls)
+	if d.ErrorUnmapped {
+		for fieldName := range protected {
+			mapped[fieldName] = true
+		}
+		if err := unmappedError(wantType, mapped); err != nil {
+			return err
+		}
+	}
+	if info.validated {
+		if err := validateStruct(newStructValue, inactive); err != nil {
+			return err
+		}
+	}
+	if info.hasAfter {
+		if err := afterMapDecode(newStructValue); err != nil {
+			return err
+		}
+	}
+	setValue(receiver, newStructValue)
+	return nil
+}
+
+// rejectProtectedPositions returns an error naming the keys of the protected fields at the positions in a tuple, if
+// there are any, as rejectProtected does for a map.
+func (d *Decoder) rejectProtectedPositions(structType reflect.Type, input reflect.Value, positions []int,
+	protected map[string]bool) error {
+	var rejected []string
+	for i := 0; i < input.Len() && i < len(positions); i++ {
+		if positions[i] >= 0 && protected[structType.Field(positions[i]).Name] {
+			rejected = append(rejected, fieldKey(structType.Field(positions[i]), d.Tags))
+		}
+	}
+	if len(rejected) == 0 {
+		return nil
+	}
+	return fmt.Errorf(protectedMsg, "'"+strings.Join(rejected, "', '")+"'", structType.Name())
+}
+
+func (d *Decoder) structToTuple(input reflect.Value) ([]interface{}, error) {
+	structType := input.Type()
+	positions, err := tuplePositions(structType)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]interface{}, len(positions))
+	for i, fieldIndex := range positions {
+		if fieldIndex < 0 || !d.inGroups(structType.Field(fieldIndex)) {
+			continue
+		}
+		value, err := d.toInterface(input.Field(fieldIndex))
+		if err != nil {
+			return nil, fmt.Errorf(structPrefix+"%s", structType.Field(fieldIndex).Name, structType.Name(), err)
+		}
+		output[i] = value
+	}
+	return output, nil
+}
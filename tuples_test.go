@@ -0,0 +1,163 @@
+package mapstostructs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Reply struct {
+	Name   string `pos:"1"`
+	ID     int    `pos:"0"`
+	Gender string `pos:"3"`
+	Age    int
+}
+
+type BadReply struct {
+	ID int `pos:"first"`
+}
+
+func TestTuples(t *testing.T) {
+	rows := []interface{}{
+		[]interface{}{float64(213), "Zhaoliu", "male", 19, []string{"football"}},
+		[]interface{}{56, "Zhangsan"},
+		map[string]interface{}{"id": 7, "name": "Lisi"},
+	}
+
+	var users []User
+
+	err := mapstostructs.SliceToSlice(rows, &users)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []User{
+			{ID: 213, Name: "Zhaoliu", Gender: "male", Age: 19, Sports: []string{"football"}},
+			{ID: 56, Name: "Zhangsan"},
+			{ID: 7, Name: "Lisi"},
+		}, users, "fields should be populated in order, and maps and tuples may be mixed")
+	}
+
+	var replies []*Reply
+
+	err = mapstostructs.Convert([][]interface{}{{1, "a", "ignored", "female", 99}}, &replies)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []*Reply{{ID: 1, Name: "a", Gender: "female"}}, replies, "fields should be populated by pos tags")
+	}
+
+	var user User
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"location": []string{"UK", "London"}}, &user)
+
+	if assert.Nil(t, err, "error should be nil for a nested tuple") {
+		assert.Equal(t, Location{Country: "UK", City: "London"}, user.Location, "nested structs should be populated from tuples")
+	}
+
+	err = mapstostructs.SliceToSlice([]interface{}{[]interface{}{"x"}}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the ID field for a struct of type User must be or be convertible to int type, but received 'x' in row 1", err.Error())
+	}
+
+	var bad BadReply
+
+	err = mapstostructs.Convert([]int{1}, &bad)
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid pos tag") {
+		assert.Equal(t, "the pos tag for the ID field for a struct of type BadReply must be a non-negative integer, but received 'first'", err.Error())
+	}
+}
+
+func TestTuplesToStructs(t *testing.T) {
+	var users []User
+
+	err := mapstostructs.TuplesToStructs([][]interface{}{{float64(213), "Zhaoliu"}, {56, "Zhangsan", "female"}}, &users)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []User{{ID: 213, Name: "Zhaoliu"}, {ID: 56, Name: "Zhangsan", Gender: "female"}}, users, "each tuple should populate a struct")
+	}
+
+	var replies []*Reply
+
+	err = mapstostructs.NewDecoder().TuplesToStructs([][]interface{}{{1, "a"}}, &replies)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []*Reply{{ID: 1, Name: "a"}}, replies, "pointers to structs should be populated")
+	}
+
+	err = mapstostructs.TuplesToStructs([][]interface{}{{"x"}}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the ID field for a struct of type User must be or be convertible to int type, but received 'x' in row 1", err.Error())
+	}
+
+	err = mapstostructs.TuplesToStructs([][]interface{}{{1}}, &[]int{})
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid receiver") {
+		assert.Equal(t, "the receiver argument must be a ptr to a slice of struct but a ptr to a slice of int was given", err.Error())
+	}
+}
+
+func TestTuplesDefaults(t *testing.T) {
+	type Row struct {
+		ID     int    `json:"id"`
+		Status string `json:"status" default:"active"`
+		Score  int    `json:"score" default:"10"`
+	}
+
+	rows, err := mapstostructs.TuplesToStructsOf[Row]([][]interface{}{{1}, {2, "closed", nil}})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []Row{{ID: 1, Status: "active", Score: 10}, {ID: 2, Status: "closed"}}, rows, "positions beyond the tuple should get defaults")
+	}
+
+	decoder := &mapstostructs.Decoder{DefaultOnNil: true}
+
+	var row Row
+
+	err = decoder.Convert([]interface{}{2, "closed", nil}, &row)
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, Row{ID: 2, Status: "closed", Score: 10}, row, "nil values should get defaults with DefaultOnNil")
+	}
+}
+
+func TestTuplesErrorUnmapped(t *testing.T) {
+	decoder := &mapstostructs.Decoder{ErrorUnmapped: true}
+
+	var replies []Reply
+
+	err := decoder.TuplesToStructs([][]interface{}{{1, "a", nil, "female"}}, &replies)
+
+	if assert.NotNil(t, err, "error should not be nil for a field without a position") {
+		assert.Equal(t, "the Age field(s) for a struct of type Reply had no input to map from in row 1", err.Error())
+	}
+
+	var users []User
+
+	err = decoder.TuplesToStructs([][]interface{}{{1, "a"}}, &users)
+
+	if assert.NotNil(t, err, "error should not be nil for a short tuple") {
+		assert.Equal(t, "the Gender, Age, Sports, Location field(s) for a struct of type User had no input to map from in row 1", err.Error())
+	}
+}
+
+func TestStructToTuple(t *testing.T) {
+	tuple, err := mapstostructs.StructToTuple(&Reply{ID: 1, Name: "a", Gender: "female", Age: 3})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []interface{}{1, "a", nil, "female"}, tuple, "values should be at their pos tag positions")
+	}
+
+	tuple, err = mapstostructs.StructToTuple(User{ID: 1, Location: Location{City: "London"}})
+
+	if assert.Nil(t, err, "error should be nil for valid call") {
+		assert.Equal(t, []interface{}{1, "", "", 0, nil, map[string]interface{}{"country": "", "city": "London"}}, tuple,
+			"values should be in field order")
+	}
+
+	_, err = mapstostructs.StructToTuple(BadReply{})
+
+	assert.NotNil(t, err, "error should not be nil with an invalid pos tag")
+}
@@ -1,54 +1,88 @@
 package mapstostructs
 
 import (
+	"encoding"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
 )
 
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
 const (
-	badValueMsg    = "must be or be convertible to %s type, but received '%v'"
-	structPrefix   = "the %s field for a struct of type %s "
-	rowSuffix      = " in row %d"
-	mapKeyPrefix   = "the map key for a %s "
-	mapValuePrefix = "the map value for a %s "
-	jsonTag        = "json"
+	badValueMsg     = "must be or be convertible to %s type, but received '%v'"
+	unmappedMsg     = "the %s field(s) for a struct of type %s had no input to map from"
+	badStructKeyMsg = "the map keys for a struct of type %s must be strings, but received %s '%v'"
+	inexactMsg      = "must be assignable to %s type without conversion, but received %s type '%v'"
+	structPrefix    = "the %s field for a struct of type %s "
+	rowSuffix       = " in row %d"
+	mapKeyPrefix    = "the map key for a %s "
+	mapValuePrefix  = "the map value for a %s "
+	jsonTag         = "json"
 )
 
 func makeTagMap(structType reflect.Type, tags []string) map[string]string {
 	numFields := structType.NumField()
 	tagMap := make(map[string]string, numFields)
-	tags = append(tags, jsonTag)
 	for i := 0; i < numFields; i++ {
 		field := structType.Field(i)
-		var tagged bool
-		for _, tagName := range tags {
-			tag, ok := field.Tag.Lookup(tagName)
-			if ok {
-				parts := strings.Split(tag, ",")
-				tagMap[strings.ToLower(parts[0])] = field.Name
-				tagged = true
-				break
-			}
+		if _, ok := field.Tag.Lookup(pathTag); ok || field.PkgPath != "" {
+			continue
 		}
-		if !tagged {
-			tagMap[strings.ToLower(field.Name)] = field.Name
+		if tag := field.Tag.Get(prefixTag); tag != "" || hasRemainOption(field, tags) || omitted(field, tags) {
+			continue
 		}
+		tagMap[strings.ToLower(fieldKey(field, tags))] = field.Name
 	}
 	return tagMap
 }
 
-func setSlice(receiver reflect.Value, input reflect.Value, tags []string) error {
+// fieldKey returns the map key for a struct field: the name from the first of the tags present on the field, or
+// the field name if none is present or the tag gives no name.
+func fieldKey(field reflect.StructField, tags []string) string {
+	if tag, ok := lookupTag(field, tags); ok {
+		if name := strings.Split(tag, ",")[0]; name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// omitted reports whether a struct field is excluded from maps by a tag name of "-", as with json:"-". A tag of "-,"
+// keys the field by "-" instead.
+func omitted(field reflect.StructField, tags []string) bool {
+	tag, ok := lookupTag(field, tags)
+	return ok && tag == "-"
+}
+
+// lookupTag returns the value of the first of the tags present on a struct field, falling back to the json tag.
+func lookupTag(field reflect.StructField, tags []string) (string, bool) {
+	for _, tagName := range tags {
+		if tag, ok := field.Tag.Lookup(tagName); ok {
+			return tag, true
+		}
+	}
+	return field.Tag.Lookup(jsonTag)
+}
+
+func (d *Decoder) setSlice(receiver reflect.Value, input reflect.Value) error {
 	if input.Len() == 0 {
 		return nil
 	}
-	elementType := receiver.Type().Elem()
-	newSliceValue := reflect.MakeSlice(reflect.SliceOf(elementType), 0, input.Len())
+	sliceType := receiver.Type()
+	if receiver.Kind() == reflect.Ptr {
+		sliceType = sliceType.Elem()
+	}
+	elementType := sliceType.Elem()
+	newSliceValue := reflect.MakeSlice(sliceType, 0, input.Len())
 	for i := 0; i < input.Len(); i++ {
 		newElement := reflect.Indirect(reflect.New(elementType))
-		if err := setRecursively(newElement, input.Index(i), tags); err != nil {
-			return fmt.Errorf(err.Error()+rowSuffix, i+1)
+		if err := d.setRecursively(newElement, input.Index(i)); err != nil {
+			return fmt.Errorf("%s"+rowSuffix, err, i+1)
 		}
 		newSliceValue = reflect.Append(newSliceValue, newElement)
 	}
@@ -57,50 +91,242 @@ func setSlice(receiver reflect.Value, input reflect.Value, tags []string) error
 	return nil
 }
 
-func setStructFromMap(receiver reflect.Value, input reflect.Value, tags []string) error {
-	if input.Len() == 0 {
-		return nil
-	}
+func (d *Decoder) setStructFromMap(receiver reflect.Value, input reflect.Value) error {
 	wantType := receiver.Type()
 	if receiver.Kind() == reflect.Ptr {
 		wantType = receiver.Type().Elem()
 	}
-	tagMap := makeTagMap(wantType, tags)
+	defaults, err := d.defaults(wantType)
+	if err != nil {
+		return err
+	}
+	info := d.structInfo(wantType)
+	if input.Len() == 0 && !d.ErrorUnmapped && defaults == nil && !info.hasBefore && !info.hasAfter && !info.validated {
+		return nil
+	}
 	newStructValue := reflect.Indirect(reflect.New(wantType))
+	if info.hasBefore {
+		if input, err = beforeMapDecode(newStructValue, input); err != nil {
+			return err
+		}
+	}
+	if d.Separator != "" {
+		if input, err = unflattenInput(input, d.Separator); err != nil {
+			return err
+		}
+	}
+	tagMap := info.tagMap
+	var mapped, nulls map[string]bool
+	if d.ErrorUnmapped || defaults != nil {
+		mapped = make(map[string]bool, len(tagMap))
+		nulls = make(map[string]bool)
+	}
+	prefixes := info.prefixes
+	prefixed := make([]map[string]interface{}, len(prefixes))
+	paths := info.paths
+	remainIndex := info.remainIndex
+	if info.remainErr != nil {
+		return info.remainErr
+	}
+	inactive := d.inactiveFields(wantType)
+	protected := d.protectedFields(wantType, info.readonly, inactive)
+	if remainIndex >= 0 && (inactive[wantType.Field(remainIndex).Name] || protected[wantType.Field(remainIndex).Name]) {
+		remainIndex = -1
+	}
+	var remain map[string]interface{}
+	if remainIndex >= 0 {
+		remain = make(map[string]interface{})
+	}
+	var (
+		setters map[string]reflect.Method
+		calls   []setterCall
+	)
+	if d.Setters {
+		setters = setterMethods(wantType)
+	}
+	roots := pathRoots(paths)
+	if d.ProtectedKeys == ProtectedKeysError {
+		err := d.rejectProtected(wantType, input, tagMap, protected, prefixes, setters, paths, remain != nil)
+		if err != nil {
+			return err
+		}
+	}
 	mapRange := input.MapRange()
 	for mapRange.Next() {
-		if fieldName, ok := tagMap[strings.ToLower(mapRange.Key().String())]; ok {
+		key := mapRange.Key()
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		if key.Kind() != reflect.String {
+			return fmt.Errorf(badStructKeyMsg, wantType.Name(), describeType(key), describeValue(key))
+		}
+		if fieldName, ok := tagMap[strings.ToLower(key.String())]; ok {
+			if protected[fieldName] || inactive[fieldName] {
+				continue
+			}
 			receivingField := newStructValue.FieldByName(fieldName)
-			inputField := mapRange.Value().Elem()
-			if err := setRecursively(receivingField, inputField, tags); err != nil {
-				return fmt.Errorf(structPrefix+err.Error(), fieldName, receiver.Type().Name())
+			if err := d.at(key.String()).setRecursively(receivingField, mapRange.Value()); err != nil {
+				return fmt.Errorf(structPrefix+"%s", fieldName, wantType.Name(), err)
 			}
+			if mapped != nil {
+				mapped[fieldName] = true
+				nulls[fieldName] = d.DefaultOnNil && isNil(mapRange.Value())
+			}
+		} else if i, nestedKey, ok := matchPrefix(prefixes, key.String()); ok {
+			if fieldName := wantType.Field(prefixes[i].index).Name; protected[fieldName] || inactive[fieldName] {
+				continue
+			}
+			if prefixed[i] == nil {
+				prefixed[i] = make(map[string]interface{})
+			}
+			prefixed[i][nestedKey] = mapRange.Value().Interface()
+		} else if setter, ok := setters[strings.ToLower(key.String())]; ok {
+			if d.protectedPath(key.String()) {
+				continue
+			}
+			calls = append(calls, setterCall{method: setter, input: mapRange.Value()})
+		} else if remain != nil && !roots[strings.ToLower(key.String())] && !d.protectedPath(key.String()) {
+			remain[key.String()] = mapRange.Value().Interface()
+		}
+	}
+	if len(remain) > 0 {
+		fieldName := wantType.Field(remainIndex).Name
+		if err := d.setRecursively(newStructValue.Field(remainIndex), reflect.ValueOf(remain)); err != nil {
+			return fmt.Errorf(structPrefix+"%s", fieldName, wantType.Name(), err)
+		}
+	}
+	if remainIndex >= 0 && mapped != nil {
+		mapped[wantType.Field(remainIndex).Name] = true
+	}
+	if err := d.callSetters(newStructValue, calls); err != nil {
+		return err
+	}
+	for i, nested := range prefixed {
+		if nested == nil {
+			continue
+		}
+		field := wantType.Field(prefixes[i].index)
+		fieldName := field.Name
+		receivingField := newStructValue.Field(prefixes[i].index)
+		if err := d.at(fieldKey(field, d.Tags)).setRecursively(receivingField, reflect.ValueOf(nested)); err != nil {
+			return fmt.Errorf(structPrefix+"%s", fieldName, wantType.Name(), err)
+		}
+		if mapped != nil {
+			mapped[fieldName] = true
+		}
+	}
+	for fieldIndex := 0; fieldIndex < wantType.NumField() && paths != nil; fieldIndex++ {
+		parts, ok := paths[fieldIndex]
+		if !ok {
+			continue
+		}
+		value, ok := resolvePath(input, parts)
+		if !ok {
+			continue
+		}
+		field := wantType.Field(fieldIndex)
+		fieldName := field.Name
+		if protected[fieldName] || inactive[fieldName] {
+			continue
+		}
+		if err := d.at(fieldKey(field, d.Tags)).setRecursively(newStructValue.Field(fieldIndex), value); err != nil {
+			return fmt.Errorf(structPrefix+"%s", fieldName, wantType.Name(), err)
+		}
+		if mapped != nil {
+			mapped[fieldName] = true
+			nulls[fieldName] = d.DefaultOnNil && isNil(value)
+		}
+	}
+	if mapped != nil {
+		// Inactive fields are neither expected in the input nor given defaults.
+		for fieldName := range inactive {
+			mapped[fieldName], nulls[fieldName] = true, false
+		}
+	}
+	setDefaults(newStructValue, defaults, mapped, nulls)
+	if d.ErrorUnmapped {
+		for fieldName := range protected {
+			mapped[fieldName] = true
+		}
+		if err := unmappedError(wantType, mapped); err != nil {
+			return err
+		}
+	}
+	if info.validated {
+		if err := validateStruct(newStructValue, inactive); err != nil {
+			return err
+		}
+	}
+	if info.hasAfter {
+		if err := afterMapDecode(newStructValue); err != nil {
+			return err
 		}
 	}
 	setValue(receiver, newStructValue)
 	return nil
 }
 
-func setMap(receiver reflect.Value, input reflect.Value, tags []string) error {
+// unmappedError names the exported fields of a struct type which are not in the mapped set, if there are any.
+func unmappedError(structType reflect.Type, mapped map[string]bool) error {
+	var unmapped []string
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath == "" && !mapped[field.Name] {
+			unmapped = append(unmapped, field.Name)
+		}
+	}
+	if len(unmapped) == 0 {
+		return nil
+	}
+	return fmt.Errorf(unmappedMsg, strings.Join(unmapped, ", "), structType.Name())
+}
+
+func (d *Decoder) setMap(receiver reflect.Value, input reflect.Value) error {
 	if input.Len() == 0 {
 		return nil
 	}
 	wantType := receiver.Type()
+	if receiver.Kind() == reflect.Ptr {
+		wantType = wantType.Elem()
+	}
+	if d.ProtectedKeys == ProtectedKeysError {
+		if err := d.rejectProtectedEntries(wantType, input); err != nil {
+			return err
+		}
+	}
 	wantKeyType := wantType.Key()
 	newMapValue := reflect.MakeMap(wantType)
 	mapRange := input.MapRange()
 
 	for mapRange.Next() {
-		key, ok := convertToType(mapRange.Key(), wantKeyType, true)
+		inputKey := mapRange.Key()
+		if inputKey.Kind() == reflect.Interface {
+			inputKey = inputKey.Elem()
+		}
+		key, handled, err := convertEnum(inputKey, wantKeyType)
+		if err != nil {
+			return fmt.Errorf(mapKeyPrefix+"%s", wantType.String(), err)
+		}
+		ok := handled
+		if !handled {
+			key, ok = d.convert(inputKey, wantKeyType, true)
+		}
 		if !ok {
-			want := wantKeyType.String()
-			have := mapRange.Key().Interface()
-			return fmt.Errorf(mapKeyPrefix+badValueMsg, wantType.String(), want, have)
+			return fmt.Errorf(mapKeyPrefix+"%s", wantType.String(), d.badValue(inputKey, wantKeyType))
 		}
 
+		child := d
+		if len(d.AllowFields) > 0 || len(d.DenyFields) > 0 {
+			// Paths continue through the keys of maps, so that "meta.public" may allow one key of a Meta map.
+			keyString, _ := formatMapKey(inputKey)
+			if d.protectedPath(keyString) {
+				continue
+			}
+			child = d.at(keyString)
+		}
 		newElement := reflect.Indirect(reflect.New(wantType.Elem()))
-		if err := setRecursively(newElement, mapRange.Value(), tags); err != nil {
-			return fmt.Errorf(mapValuePrefix+err.Error(), wantType.String())
+		if err := child.setRecursively(newElement, mapRange.Value()); err != nil {
+			return fmt.Errorf(mapValuePrefix+"%s", wantType.String(), err)
 		}
 		newMapValue.SetMapIndex(key, newElement)
 	}
@@ -108,35 +334,193 @@ func setMap(receiver reflect.Value, input reflect.Value, tags []string) error {
 	return nil
 }
 
-func setRecursively(receiver reflect.Value, input reflect.Value, tags []string) error {
+func (d *Decoder) setRecursively(receiver reflect.Value, input reflect.Value) error {
 	if input.Kind() == reflect.Ptr || input.Kind() == reflect.Interface {
-		return setRecursively(receiver, input.Elem(), tags)
+		return d.setRecursively(receiver, input.Elem())
+	}
+	if !input.IsValid() || (input.Kind() == reflect.Map && input.IsNil()) {
+		return nil
+	}
+	if receiver.Kind() == reflect.Ptr && receiver.Type().Elem().Kind() == reflect.Ptr {
+		// Populate a new pointer for each level of indirection beyond the one handled by setValue.
+		holder := reflect.New(receiver.Type().Elem())
+		if err := d.setRecursively(holder.Elem(), input); err != nil {
+			return err
+		}
+		receiver.Set(holder)
+		return nil
 	}
-	have := input.Interface()
 	wantType := receiver.Type()
 	if wantType.Kind() == reflect.Ptr {
 		wantType = wantType.Elem()
 	}
-	want := wantType.String()
 
-	if valueToSet, ok := convertToType(input, wantType, false); ok {
+	switch {
+	case wantType == rawValueType && input.Type() == rawValueType:
+		setValue(receiver, input)
+		return nil
+	case wantType == rawValueType:
+		setValue(receiver, d.rawValue(input))
+		return nil
+	case input.Type() == rawValueType:
+		return d.setRecursively(receiver, input.FieldByName("Value"))
+	case input.Type() != wantType && reflect.PtrTo(wantType).Implements(mapUnmarshalerType):
+		valueToSet, err := unmarshalMap(input, wantType)
+		if err != nil {
+			return err
+		}
+		setValue(receiver, valueToSet)
+		return nil
+	}
+
+	if valueToSet, handled, err := convertEnum(input, wantType); handled {
+		if err != nil {
+			return err
+		}
+		setValue(receiver, valueToSet)
+		return nil
+	}
+
+	if input.Kind() == reflect.Map && (len(d.AllowFields) > 0 || len(d.DenyFields) > 0) {
+		// Maps are copied key by key rather than assigned, so that field paths reach their entries.
+		if wantType.Kind() == reflect.Map {
+			return d.setMap(receiver, input)
+		}
+		if wantType.Kind() == reflect.Interface && input.Type().AssignableTo(wantType) {
+			copied := reflect.New(input.Type()).Elem()
+			if err := d.setMap(copied, input); err != nil {
+				return err
+			}
+			setValue(receiver, copied)
+			return nil
+		}
+	}
+
+	if valueToSet, ok := d.convert(input, wantType, false); ok {
 		setValue(receiver, valueToSet)
 		return nil
 	}
 
-	if wantType.Kind() == reflect.Struct && input.Kind() == reflect.Map && input.Type().Key().Kind() == reflect.String {
-		return setStructFromMap(receiver, input, tags)
+	if input.Kind() == reflect.Struct && (wantType.Kind() == reflect.Struct || wantType.Kind() == reflect.Map) &&
+		hasExportedFields(input.Type()) {
+		// Match the fields of a struct of another type by tag, or populate a map from them, as StructToMap would.
+		output, err := d.structToMap(input)
+		if err != nil {
+			return err
+		}
+		input = reflect.ValueOf(output)
+	}
+
+	if wantType.Kind() == reflect.Struct && input.Kind() == reflect.Map &&
+		(input.Type().Key().Kind() == reflect.String || input.Type().Key().Kind() == reflect.Interface) {
+		return d.setStructFromMap(receiver, input)
+	}
+
+	if wantType.Kind() == reflect.Struct && (input.Kind() == reflect.Slice || input.Kind() == reflect.Array) &&
+		hasExportedFields(wantType) {
+		return d.setStructFromTuple(receiver, input)
+	}
+
+	if wantType.Kind() == reflect.Slice && (input.Kind() == reflect.Slice || input.Kind() == reflect.Array) {
+		return d.setSlice(receiver, input)
 	}
 
-	if wantType.Kind() == reflect.Slice && input.Kind() == reflect.Slice {
-		return setSlice(receiver, input, tags)
+	if (d.IndexMapsToSlices || d.Separator != "") &&
+		(wantType.Kind() == reflect.Slice || wantType.Kind() == reflect.Array) && input.Kind() == reflect.Map {
+		return d.setSliceFromIndexMap(receiver, input)
+	}
+
+	if d.SlicesToIndexMaps && wantType.Kind() == reflect.Map &&
+		(input.Kind() == reflect.Slice || input.Kind() == reflect.Array) {
+		input = indexMap(input)
 	}
 
 	if wantType.Kind() == reflect.Map && input.Kind() == reflect.Map {
-		return setMap(receiver, input, tags)
+		return d.setMap(receiver, input)
+	}
+
+	return d.badValue(input, wantType)
+}
+
+// convert is convertToType restricted to assignable values when the Decoder is in exact mode.
+func (d *Decoder) convert(input reflect.Value, wantType reflect.Type, convertMapIndexes bool) (reflect.Value, bool) {
+	if d.Exact {
+		if input.IsValid() && input.Type().AssignableTo(wantType) {
+			return input, true
+		}
+		return reflect.Value{}, false
+	}
+	return convertToType(input, wantType, convertMapIndexes)
+}
+
+func (d *Decoder) badValue(input reflect.Value, wantType reflect.Type) error {
+	if d.Exact {
+		return fmt.Errorf(inexactMsg, wantType.String(), describeType(input), describeValue(input))
+	}
+	return fmt.Errorf(badValueMsg, wantType.String(), describeValue(input))
+}
+
+func describeType(input reflect.Value) string {
+	if !input.IsValid() {
+		return "nil"
+	}
+	return input.Type().String()
+}
+
+func describeValue(input reflect.Value) interface{} {
+	if !input.IsValid() {
+		return nil
+	}
+	return input.Interface()
+}
+
+// normalize deep-converts maps with string keys, or interface keys holding values formattable as strings, into
+// map[string]interface{} and slices into []interface{}. Other values are returned unchanged.
+func normalize(input reflect.Value) (interface{}, error) {
+	if input.Kind() == reflect.Interface {
+		return normalize(input.Elem())
+	}
+
+	switch input.Kind() {
+
+	case reflect.Invalid:
+		return nil, nil
+
+	case reflect.Slice, reflect.Array:
+		if input.Kind() == reflect.Slice && input.IsNil() {
+			return nil, nil
+		}
+		output := make([]interface{}, input.Len())
+		for i := 0; i < input.Len(); i++ {
+			value, err := normalize(input.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("%s"+rowSuffix, err, i+1)
+			}
+			output[i] = value
+		}
+		return output, nil
+
+	case reflect.Map:
+		if input.IsNil() {
+			return nil, nil
+		}
+		output := make(map[string]interface{}, input.Len())
+		mapRange := input.MapRange()
+		for mapRange.Next() {
+			key, ok := formatMapKey(mapRange.Key())
+			if !ok {
+				return nil, fmt.Errorf(mapKeyPrefix+badValueMsg, input.Type().String(), "string", describeValue(mapRange.Key()))
+			}
+			value, err := normalize(mapRange.Value())
+			if err != nil {
+				return nil, fmt.Errorf(mapValuePrefix+"%s", input.Type().String(), err)
+			}
+			output[key] = value
+		}
+		return output, nil
 	}
 
-	return fmt.Errorf(badValueMsg, want, have)
+	return input.Interface(), nil
 }
 
 func convertToType(input reflect.Value, wantType reflect.Type, convertMapIndexes bool) (reflect.Value, bool) {
@@ -146,41 +530,81 @@ func convertToType(input reflect.Value, wantType reflect.Type, convertMapIndexes
 		}
 
 		// Number to string conversions will produce ASCII values and are not wanted.
-		if wantType.Kind() != reflect.String && input.CanConvert(wantType) {
+		if (wantType.Kind() != reflect.String || input.Kind() == reflect.String) && input.CanConvert(wantType) {
 			return input.Convert(wantType), true
 		}
 
 		if convertMapIndexes && input.Kind() == reflect.String {
-			// Support reverse string to number parsing conversions for maps with numeric keys converted to strings
+			// Support reverse string parsing conversions for maps with numeric and other keys converted to strings
 			// in JSON representations, etc.
-			var (
-				parsed reflect.Value
-				ok     bool
-			)
-			stringVar := input.String()
-			switch wantType.Kind() {
-
-			case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
-				int64Var, err := strconv.ParseInt(stringVar, 10, 64)
-				parsed, ok = reflect.ValueOf(int64Var), err == nil
-
-			case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
-				uint64Var, err := strconv.ParseUint(stringVar, 10, 64)
-				parsed, ok = reflect.ValueOf(uint64Var), err == nil
-
-			case reflect.Float64, reflect.Float32:
-				float64Var, err := strconv.ParseFloat(stringVar, 64)
-				parsed, ok = reflect.ValueOf(float64Var), err == nil
-			}
+			return parseString(input.String(), wantType)
+		}
 
-			if ok {
-				return convertToType(parsed, wantType, false)
+		if convertMapIndexes && wantType.Kind() == reflect.String {
+			if formatted, ok := formatMapKey(input); ok {
+				return reflect.ValueOf(formatted).Convert(wantType), true
 			}
 		}
 	}
 	return reflect.Value{}, false
 }
 
+// parseString parses a string into a type implementing encoding.TextUnmarshaler or of a bool or numeric kind. Integers
+// may be given in hexadecimal with a 0x prefix.
+func parseString(stringVar string, wantType reflect.Type) (reflect.Value, bool) {
+	if reflect.PtrTo(wantType).Implements(textUnmarshalerType) {
+		parsed := reflect.New(wantType)
+		err := parsed.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(stringVar))
+		return parsed.Elem(), err == nil
+	}
+
+	var (
+		parsed reflect.Value
+		err    error
+	)
+	switch wantType.Kind() {
+
+	case reflect.Bool:
+		var boolVar bool
+		boolVar, err = strconv.ParseBool(stringVar)
+		parsed = reflect.ValueOf(boolVar)
+
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		var int64Var int64
+		base, digits := integerBase(stringVar)
+		int64Var, err = strconv.ParseInt(digits, base, wantType.Bits())
+		parsed = reflect.ValueOf(int64Var)
+
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		var uint64Var uint64
+		base, digits := integerBase(stringVar)
+		uint64Var, err = strconv.ParseUint(digits, base, wantType.Bits())
+		parsed = reflect.ValueOf(uint64Var)
+
+	case reflect.Float64, reflect.Float32:
+		var float64Var float64
+		float64Var, err = strconv.ParseFloat(stringVar, wantType.Bits())
+		parsed = reflect.ValueOf(float64Var)
+
+	default:
+		return reflect.Value{}, false
+	}
+
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	return convertToType(parsed, wantType, false)
+}
+
+// integerBase returns the base of an integer string, which is 16 for a 0x or 0X prefix and 10 otherwise, and its
+// digits without the prefix.
+func integerBase(stringVar string) (int, string) {
+	if len(stringVar) > 2 && stringVar[0] == '0' && (stringVar[1] == 'x' || stringVar[1] == 'X') {
+		return 16, stringVar[2:]
+	}
+	return 10, stringVar
+}
+
 func setValue(receiver reflect.Value, input reflect.Value) {
 	if receiver.Kind() == reflect.Ptr {
 		receiver.Set(reflect.New(receiver.Type().Elem()))
@@ -0,0 +1,313 @@
+package mapstostructs
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	validateTag          = "validate"
+	regexValidator       = "regex"
+	nonzeroValidator     = "nonzero"
+	badValidatorNameMsg  = "the validator name '%s' must not be empty or contain ',' or '='"
+	nilValidatorMsg      = "the validator argument must not be nil"
+	unknownValidatorMsg  = "the validator '%s' for the %s field for a struct of type %s is not registered"
+	badValidatorParamMsg = "the %s validator for the %s field for a struct of type %s cannot use the parameter '%s'"
+	minMsg               = "must be at least %s, but received '%v'"
+	maxMsg               = "must be at most %s, but received '%v'"
+	minLenMsg            = "must have a length of at least %s, but received '%v'"
+	maxLenMsg            = "must have a length of at most %s, but received '%v'"
+	lenMsg               = "must have a length of %s, but received '%v'"
+	oneOfMsg             = "must be one of (%s), but received '%v'"
+	regexMsg             = "must match the pattern '%s', but received '%v'"
+	emailMsg             = "must be an email address, but received '%v'"
+	urlMsg               = "must be a URL, but received '%v'"
+	nonzeroMsg           = "must not be a zero value, but received '%v'"
+)
+
+// A Validator checks a field value after conversion, given the parameter following its name in a validate tag, such
+// as "10" for min=10, or an empty string if there is none. Pointers are dereferenced before a value is validated, and
+// a nil pointer is only validated by nonzero.
+//
+// A Validator returns an error describing the failure in the form "must be ..., but received '...'", which is prefixed
+// with the field and struct type in the same way as conversion errors.
+type Validator func(value interface{}, param string) error
+
+// errParam is returned by a built-in Validator which cannot use its parameter or the type of the value.
+var errParam = fmt.Errorf("bad parameter")
+
+var (
+	validatorMutex sync.RWMutex
+	validators     = map[string]Validator{
+		"min":            validateMin,
+		"max":            validateMax,
+		"len":            validateLen,
+		"oneof":          validateOneOf,
+		regexValidator:   validateRegex,
+		"email":          validateEmail,
+		"url":            validateURL,
+		nonzeroValidator: validateNonzero,
+	}
+
+	regexCache sync.Map
+)
+
+// RegisterValidator registers a Validator by name, so that it can be used in validate tags such as
+// validate:"name=param". Registering a name again, including a built-in name, replaces its previous Validator.
+func RegisterValidator(name string, validator Validator) error {
+	if name == "" || strings.ContainsAny(name, ",=") {
+		return fmt.Errorf(badValidatorNameMsg, name)
+	}
+	if validator == nil {
+		return fmt.Errorf(nilValidatorMsg)
+	}
+	validatorMutex.Lock()
+	defer validatorMutex.Unlock()
+	validators[name] = validator
+	return nil
+}
+
+func lookupValidator(name string) (Validator, bool) {
+	validatorMutex.RLock()
+	defer validatorMutex.RUnlock()
+	validator, ok := validators[name]
+	return validator, ok
+}
+
+// validateStruct runs the validators in the validate tags of the exported fields of a populated struct, other than
+// the inactive fields, in field order, returning the first failure.
+func validateStruct(structValue reflect.Value, inactive map[string]bool) error {
+	structType := structValue.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag, ok := field.Tag.Lookup(validateTag)
+		if !ok || field.PkgPath != "" || inactive[field.Name] {
+			continue
+		}
+		value := structValue.Field(i)
+		for (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && !value.IsNil() {
+			value = value.Elem()
+		}
+		isNil := value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface
+		for _, rule := range splitRules(tag) {
+			name, param := rule, ""
+			if index := strings.Index(rule, "="); index >= 0 {
+				name, param = rule[:index], rule[index+1:]
+			}
+			validator, ok := lookupValidator(name)
+			if !ok {
+				return fmt.Errorf(unknownValidatorMsg, name, field.Name, structType.Name())
+			}
+			if isNil && name != nonzeroValidator {
+				continue
+			}
+			if err := validator(value.Interface(), param); err == errParam {
+				return fmt.Errorf(badValidatorParamMsg, name, field.Name, structType.Name(), param)
+			} else if err != nil {
+				return fmt.Errorf(structPrefix+"%s", field.Name, structType.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// splitRules splits a validate tag into its comma-separated rules. A regex rule takes the rest of the tag, so that
+// its pattern may contain commas.
+func splitRules(tag string) []string {
+	var rules []string
+	for tag != "" {
+		if strings.HasPrefix(tag, regexValidator+"=") {
+			return append(rules, tag)
+		}
+		rule := tag
+		if i := strings.Index(tag, ","); i >= 0 {
+			rule, tag = tag[:i], tag[i+1:]
+		} else {
+			tag = ""
+		}
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// validateMin checks that a number is at least the parameter, or that a string, slice, array or map has at least
+// that length.
+func validateMin(value interface{}, param string) error {
+	compared, isLength, err := compareToParam(value, param)
+	switch {
+	case err != nil:
+		return err
+	case compared < 0 && isLength:
+		return fmt.Errorf(minLenMsg, param, value)
+	case compared < 0:
+		return fmt.Errorf(minMsg, param, value)
+	}
+	return nil
+}
+
+// validateMax checks that a number is at most the parameter, or that a string, slice, array or map has at most that
+// length.
+func validateMax(value interface{}, param string) error {
+	compared, isLength, err := compareToParam(value, param)
+	switch {
+	case err != nil:
+		return err
+	case compared > 0 && isLength:
+		return fmt.Errorf(maxLenMsg, param, value)
+	case compared > 0:
+		return fmt.Errorf(maxMsg, param, value)
+	}
+	return nil
+}
+
+// validateLen checks that a string, slice, array or map has the length given by the parameter.
+func validateLen(value interface{}, param string) error {
+	compared, isLength, err := compareToParam(value, param)
+	switch {
+	case err != nil || !isLength:
+		return errParam
+	case compared != 0:
+		return fmt.Errorf(lenMsg, param, value)
+	}
+	return nil
+}
+
+// compareToParam compares a number, or the length of a string, slice, array or map, with the parameter, which is a
+// duration for a time.Duration. The result is negative, zero or positive as the value is less than, equal to or
+// greater than the parameter.
+func compareToParam(value interface{}, param string) (int, bool, error) {
+	reflectValue := reflect.ValueOf(value)
+	var (
+		actual, limit float64
+		isLength      bool
+		err           error
+	)
+	switch reflectValue.Kind() {
+
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		var length int
+		length, err = strconv.Atoi(param)
+		actual, limit, isLength = float64(reflectValue.Len()), float64(length), true
+
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		actual = float64(reflectValue.Int())
+		if reflectValue.Type() == durationType {
+			var duration time.Duration
+			duration, err = time.ParseDuration(param)
+			limit = float64(duration)
+		} else {
+			limit, err = strconv.ParseFloat(param, 64)
+		}
+
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		actual = float64(reflectValue.Uint())
+		limit, err = strconv.ParseFloat(param, 64)
+
+	case reflect.Float64, reflect.Float32:
+		actual = reflectValue.Float()
+		limit, err = strconv.ParseFloat(param, 64)
+
+	default:
+		return 0, false, errParam
+	}
+	switch {
+	case err != nil:
+		return 0, false, errParam
+	case actual < limit:
+		return -1, isLength, nil
+	case actual > limit:
+		return 1, isLength, nil
+	}
+	return 0, isLength, nil
+}
+
+// validateOneOf checks that a value is one of the space-separated options in the parameter, comparing the value as it
+// would be formatted as a map key, so that registered enum values are compared by name.
+func validateOneOf(value interface{}, param string) error {
+	formatted, ok := formatMapKey(reflect.ValueOf(value))
+	if !ok {
+		return errParam
+	}
+	options := strings.Fields(param)
+	for _, option := range options {
+		if option == formatted {
+			return nil
+		}
+	}
+	return fmt.Errorf(oneOfMsg, strings.Join(options, ", "), value)
+}
+
+// validateRegex checks that a string matches the regular expression in the parameter.
+func validateRegex(value interface{}, param string) error {
+	stringVar, ok := stringValue(value)
+	if !ok {
+		return errParam
+	}
+	var pattern *regexp.Regexp
+	if cached, ok := regexCache.Load(param); ok {
+		pattern = cached.(*regexp.Regexp)
+	} else {
+		var err error
+		if pattern, err = regexp.Compile(param); err != nil {
+			return errParam
+		}
+		regexCache.Store(param, pattern)
+	}
+	if !pattern.MatchString(stringVar) {
+		return fmt.Errorf(regexMsg, param, value)
+	}
+	return nil
+}
+
+// validateEmail checks that a string is a bare email address, such as zhaoliu@example.com.
+func validateEmail(value interface{}, param string) error {
+	stringVar, ok := stringValue(value)
+	if !ok || param != "" {
+		return errParam
+	}
+	if address, err := mail.ParseAddress(stringVar); err != nil || address.Address != stringVar {
+		return fmt.Errorf(emailMsg, value)
+	}
+	return nil
+}
+
+// validateURL checks that a string is an absolute URL with a scheme and a host.
+func validateURL(value interface{}, param string) error {
+	stringVar, ok := stringValue(value)
+	if !ok || param != "" {
+		return errParam
+	}
+	if parsed, err := url.ParseRequestURI(stringVar); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf(urlMsg, value)
+	}
+	return nil
+}
+
+// validateNonzero checks that a value is not the zero value of its type, such as an empty string or a nil pointer.
+func validateNonzero(value interface{}, param string) error {
+	if param != "" {
+		return errParam
+	}
+	if value == nil || reflect.ValueOf(value).IsZero() {
+		return fmt.Errorf(nonzeroMsg, value)
+	}
+	return nil
+}
+
+// stringValue returns the value of a string or of a type with an underlying string kind.
+func stringValue(value interface{}) (string, bool) {
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() != reflect.String {
+		return "", false
+	}
+	return reflectValue.String(), true
+}
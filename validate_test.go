@@ -0,0 +1,296 @@
+package mapstostructs_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/merlincox/mapstostructs"
+)
+
+type Signup struct {
+	Name    string        `json:"name" validate:"nonzero,min=2,max=10"`
+	Age     int           `json:"age" validate:"min=18,max=130"`
+	Email   string        `json:"email" validate:"email"`
+	Website *string       `json:"website" validate:"url"`
+	Code    string        `json:"code" validate:"len=3,regex=^[A-Z]{2,3}$"`
+	Plan    string        `json:"plan" validate:"oneof=free pro"`
+	Status  Status        `json:"status" validate:"oneof=active suspended"`
+	Tags    []string      `json:"tags" validate:"max=2"`
+	Timeout time.Duration `json:"timeout" validate:"max=1m"`
+}
+
+type BadRule struct {
+	Name string `json:"name" validate:"min=two"`
+}
+
+type UnknownRule struct {
+	Name string `json:"name" validate:"odd"`
+}
+
+func TestValidateTag(t *testing.T) {
+	input := map[string]interface{}{
+		"name":    "Zhaoliu",
+		"age":     30,
+		"email":   "zhaoliu@example.com",
+		"website": "https://example.com/zhaoliu",
+		"code":    "GBR",
+		"plan":    "pro",
+		"status":  "active",
+		"tags":    []string{"a"},
+		"timeout": time.Second,
+	}
+
+	var signup Signup
+
+	err := mapstostructs.MapToStruct(input, &signup)
+
+	if assert.Nil(t, err, "error should be nil for valid data") {
+		assert.Equal(t, "Zhaoliu", signup.Name, "values should be correctly set")
+	}
+}
+
+func TestValidateNilPointer(t *testing.T) {
+	type Page struct {
+		Website *string `json:"website" validate:"url"`
+	}
+
+	var page Page
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"website": nil}, &page)
+
+	assert.Nil(t, err, "error should be nil for a nil pointer")
+}
+
+func TestValidateNonzero(t *testing.T) {
+	var signup Signup
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"name": ""}, &signup)
+
+	if assert.NotNil(t, err, "error should not be nil with a zero value") {
+		assert.Equal(t, "the Name field for a struct of type Signup must not be a zero value, but received ''", err.Error())
+	}
+}
+
+func TestValidateMinMax(t *testing.T) {
+	type Limits struct {
+		Name    string        `json:"name" validate:"min=2"`
+		Age     int           `json:"age" validate:"min=18"`
+		Tags    []string      `json:"tags" validate:"max=2"`
+		Timeout time.Duration `json:"timeout" validate:"max=1m"`
+	}
+
+	var limits Limits
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"name": "Z", "age": 18}, &limits)
+
+	if assert.NotNil(t, err, "error should not be nil with a short string") {
+		assert.Equal(t, "the Name field for a struct of type Limits must have a length of at least 2, but received 'Z'", err.Error())
+	}
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"name": "Zhaoliu", "age": 12}, &limits)
+
+	if assert.NotNil(t, err, "error should not be nil with a small number") {
+		assert.Equal(t, "the Age field for a struct of type Limits must be at least 18, but received '12'", err.Error())
+	}
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"name": "Zhaoliu", "age": 18, "tags": []string{"a", "b", "c"}}, &limits)
+
+	if assert.NotNil(t, err, "error should not be nil with a long slice") {
+		assert.Equal(t, "the Tags field for a struct of type Limits must have a length of at most 2, but received '[a b c]'", err.Error())
+	}
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"name": "Zhaoliu", "age": 18, "timeout": time.Hour}, &limits)
+
+	if assert.NotNil(t, err, "error should not be nil with a long duration") {
+		assert.Equal(t, "the Timeout field for a struct of type Limits must be at most 1m, but received '1h0m0s'", err.Error())
+	}
+}
+
+func TestValidateLenAndRegex(t *testing.T) {
+	type Country struct {
+		Code string `json:"code" validate:"len=3,regex=^[A-Z]{2,3}$"`
+	}
+
+	var country Country
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"code": "GB"}, &country)
+
+	if assert.NotNil(t, err, "error should not be nil with the wrong length") {
+		assert.Equal(t, "the Code field for a struct of type Country must have a length of 3, but received 'GB'", err.Error())
+	}
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"code": "gbr"}, &country)
+
+	if assert.NotNil(t, err, "error should not be nil without a match") {
+		assert.Equal(t, "the Code field for a struct of type Country must match the pattern '^[A-Z]{2,3}$', but received 'gbr'", err.Error())
+	}
+}
+
+func TestValidateEmailAndURL(t *testing.T) {
+	type Contact struct {
+		Email   string `json:"email" validate:"email"`
+		Website string `json:"website" validate:"url"`
+	}
+
+	var contact Contact
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"email": "Zhaoliu <zhaoliu@example.com>"}, &contact)
+
+	if assert.NotNil(t, err, "error should not be nil with a named address") {
+		assert.Equal(t, "the Email field for a struct of type Contact must be an email address, but received 'Zhaoliu <zhaoliu@example.com>'", err.Error())
+	}
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"email": "zhaoliu@example.com", "website": "example.com"}, &contact)
+
+	if assert.NotNil(t, err, "error should not be nil without a scheme") {
+		assert.Equal(t, "the Website field for a struct of type Contact must be a URL, but received 'example.com'", err.Error())
+	}
+}
+
+func TestValidateOneOf(t *testing.T) {
+	type Subscription struct {
+		Plan   string `json:"plan" validate:"oneof=free pro"`
+		Status Status `json:"status" validate:"oneof=active suspended"`
+	}
+
+	var subscription Subscription
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"plan": "gold", "status": "active"}, &subscription)
+
+	if assert.NotNil(t, err, "error should not be nil with an unlisted string") {
+		assert.Equal(t, "the Plan field for a struct of type Subscription must be one of (free, pro), but received 'gold'", err.Error())
+	}
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"plan": "free", "status": "unknown"}, &subscription)
+
+	if assert.NotNil(t, err, "error should not be nil with an unlisted enum") {
+		assert.Equal(t, "the Status field for a struct of type Subscription must be one of (active, suspended), but received 'unknown'", err.Error())
+	}
+}
+
+func TestValidateRows(t *testing.T) {
+	type Member struct {
+		Age int `json:"age" validate:"min=18"`
+	}
+
+	var members []Member
+
+	err := mapstostructs.MapsToStructs([]map[string]interface{}{{"age": 30}, {"age": 17}}, &members)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the Age field for a struct of type Member must be at least 18, but received '17' in row 2", err.Error())
+	}
+}
+
+func TestValidateBadParameter(t *testing.T) {
+	var bad BadRule
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"name": "x"}, &bad)
+
+	if assert.NotNil(t, err, "error should not be nil with a bad parameter") {
+		assert.Equal(t, "the min validator for the Name field for a struct of type BadRule cannot use the parameter 'two'", err.Error())
+	}
+}
+
+func TestValidateUnknownValidator(t *testing.T) {
+	var unknown UnknownRule
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{"name": "x"}, &unknown)
+
+	if assert.NotNil(t, err, "error should not be nil with an unregistered validator") {
+		assert.Equal(t, "the validator 'odd' for the Name field for a struct of type UnknownRule is not registered", err.Error())
+	}
+}
+
+func TestRegisterValidator(t *testing.T) {
+	type Referral struct {
+		Referrer string `json:"referrer" validate:"even"`
+	}
+
+	err := mapstostructs.RegisterValidator("even", func(value interface{}, param string) error {
+		if len(fmt.Sprint(value))%2 != 0 {
+			return fmt.Errorf("must have an even length, but received '%v'", value)
+		}
+		return nil
+	})
+
+	assert.Nil(t, err, "error should be nil for valid call")
+
+	var referral Referral
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"referrer": "ab"}, &referral)
+
+	assert.Nil(t, err, "error should be nil for valid data")
+
+	err = mapstostructs.MapToStruct(map[string]interface{}{"referrer": "abc"}, &referral)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the Referrer field for a struct of type Referral must have an even length, but received 'abc'", err.Error())
+	}
+}
+
+func TestRegisterValidatorBadName(t *testing.T) {
+	err := mapstostructs.RegisterValidator("a=b", func(interface{}, string) error { return nil })
+
+	if assert.NotNil(t, err, "error should not be nil with an invalid name") {
+		assert.True(t, strings.HasPrefix(err.Error(), "the validator name 'a=b'"))
+	}
+}
+
+func TestValidateEmptyMap(t *testing.T) {
+	type Login struct {
+		Name string `json:"name" validate:"nonzero"`
+	}
+
+	var login Login
+
+	err := mapstostructs.MapToStruct(map[string]interface{}{}, &login)
+
+	if assert.NotNil(t, err, "error should not be nil for an empty map") {
+		assert.Equal(t, "the Name field for a struct of type Login must not be a zero value, but received ''", err.Error())
+	}
+
+	var logins []Login
+
+	err = mapstostructs.MapsToStructs([]map[string]interface{}{{"name": "Zhaoliu"}, {}}, &logins)
+
+	if assert.NotNil(t, err, "error should not be nil for an empty row") {
+		assert.Equal(t, "the Name field for a struct of type Login must not be a zero value, but received '' in row 2", err.Error())
+	}
+}
+
+func TestValidateErrorWithPercent(t *testing.T) {
+	type Contact struct {
+		Email string `json:"email" validate:"email"`
+	}
+
+	var contacts []Contact
+
+	err := mapstostructs.MapsToStructs([]map[string]interface{}{{"email": "a%sb"}}, &contacts)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the Email field for a struct of type Contact must be an email address, but received 'a%sb' in row 1", err.Error())
+	}
+
+	err = mapstostructs.RegisterValidator("percent", func(value interface{}, param string) error {
+		return fmt.Errorf("must be a percentage, but received '%v'", value)
+	})
+
+	assert.Nil(t, err, "error should be nil for valid call")
+
+	type Rate struct {
+		Value string `json:"value" validate:"percent"`
+	}
+
+	var rates []Rate
+
+	err = mapstostructs.MapsToStructs([]map[string]interface{}{{"value": "5%d"}}, &rates)
+
+	if assert.NotNil(t, err, "error should not be nil with invalid data") {
+		assert.Equal(t, "the Value field for a struct of type Rate must be a percentage, but received '5%d' in row 1", err.Error())
+	}
+}